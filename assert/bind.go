@@ -0,0 +1,54 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// BindJSON decodes the JSON response body into the value pointed by out,
+// such as a struct pointer. Unknown fields are ignored.
+func BindJSON(out interface{}) Func {
+	return bindJSON(out, false)
+}
+
+// BindJSONStrict decodes the JSON response body into the value pointed by out,
+// failing if the body contains fields not defined by the destination struct.
+func BindJSONStrict(out interface{}) Func {
+	return bindJSON(out, true)
+}
+
+func bindJSON(out interface{}, strict bool) Func {
+	return func(res *http.Response, req *http.Request) error {
+		body, err := readBodyJSON(res)
+		if err != nil {
+			return err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		if err := decoder.Decode(out); err != nil {
+			return fmt.Errorf("failed to bind JSON body: %s", err)
+		}
+		return nil
+	}
+}
+
+// BindXML decodes the XML response body into the value pointed by out,
+// such as a struct pointer. Unknown elements and attributes are ignored.
+func BindXML(out interface{}) Func {
+	return func(res *http.Response, req *http.Request) error {
+		body, err := readBody(res)
+		if err != nil {
+			return err
+		}
+		if err := xml.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to bind XML body: %s", err)
+		}
+		return nil
+	}
+}
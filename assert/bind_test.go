@@ -0,0 +1,53 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+type bindUser struct {
+	ID   int    `json:"id" xml:"id,attr"`
+	Name string `json:"name" xml:"name"`
+}
+
+func bindResponse(body string) *http.Response {
+	return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+}
+
+func TestBindJSON(t *testing.T) {
+	var user bindUser
+	res := bindResponse(`{"id": 1, "name": "foo", "role": "admin"}`)
+	st.Expect(t, BindJSON(&user)(res, nil), nil)
+	st.Expect(t, user, bindUser{ID: 1, Name: "foo"})
+
+	// The body is re-filled after binding
+	body, _ := ioutil.ReadAll(res.Body)
+	st.Expect(t, string(body), `{"id": 1, "name": "foo", "role": "admin"}`)
+}
+
+func TestBindJSONInvalid(t *testing.T) {
+	var user bindUser
+	err := BindJSON(&user)(bindResponse(`{"id": "foo"}`), nil)
+	st.Reject(t, err, nil)
+}
+
+func TestBindJSONStrict(t *testing.T) {
+	var user bindUser
+	st.Expect(t, BindJSONStrict(&user)(bindResponse(`{"id": 1, "name": "foo"}`), nil), nil)
+	st.Expect(t, user, bindUser{ID: 1, Name: "foo"})
+
+	err := BindJSONStrict(&user)(bindResponse(`{"id": 1, "role": "admin"}`), nil)
+	st.Expect(t, err.Error(), `failed to bind JSON body: json: unknown field "role"`)
+}
+
+func TestBindXML(t *testing.T) {
+	var user bindUser
+	st.Expect(t, BindXML(&user)(bindResponse(`<user id="2"><name>bar</name><role>admin</role></user>`), nil), nil)
+	st.Expect(t, user, bindUser{ID: 2, Name: "bar"})
+
+	st.Reject(t, BindXML(&user)(bindResponse(`<user>`), nil), nil)
+}
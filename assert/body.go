@@ -1,33 +1,28 @@
 package assert
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"regexp"
 	"strconv"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
 )
 
 func readBody(res *http.Response) ([]byte, error) {
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-	// Re-fill body reader stream after reading it
-	res.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-	return body, err
+	return httputil.ReadResponseBody(res)
 }
 
 // BodyMatchString asserts a response body matching a string expression.
 // Regular expressions can be used as value to perform the specific assertions.
+// The body is decoded based on the response charset.
 func BodyMatchString(pattern string) Func {
 	return func(res *http.Response, req *http.Request) error {
-		body, err := readBody(res)
+		body, err := readBodyText(res)
 		if err != nil {
 			return err
 		}
-		if match, _ := regexp.MatchString(pattern, string(body)); !match {
+		if match, _ := regexp.MatchString(pattern, body); !match {
 			return fmt.Errorf("body mismatch: pattern not found '%s'", pattern)
 		}
 		return nil
@@ -36,14 +31,14 @@ func BodyMatchString(pattern string) Func {
 
 // BodyEquals asserts as strict equality comparison the
 // response body with a given string string.
+// The body is decoded based on the response charset.
 func BodyEquals(value string) Func {
 	return func(res *http.Response, req *http.Request) error {
-		body, err := readBody(res)
+		bodyStr, err := readBodyText(res)
 		if err != nil {
 			return err
 		}
 
-		bodyStr := string(body)
 		err = fmt.Errorf("bodies mismatch:\n\thave: %#v\n\twant: %#v", bodyStr, value)
 
 		// Remove line feed sequence
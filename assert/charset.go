@@ -0,0 +1,107 @@
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// byte order marks by encoding name.
+var boms = []struct {
+	name string
+	bom  []byte
+}{
+	{"utf-8", []byte{0xEF, 0xBB, 0xBF}},
+	{"utf-16be", []byte{0xFE, 0xFF}},
+	{"utf-16le", []byte{0xFF, 0xFE}},
+}
+
+// mediaCharset returns the charset parameter of the response Content-Type header, if present.
+func mediaCharset(res *http.Response) string {
+	_, params, err := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// charsetName returns the canonical name of the given charset label,
+// such as "latin1" or "Shift_JIS".
+func charsetName(label string) (string, error) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return "", err
+	}
+	return htmlindex.Name(enc)
+}
+
+// decodeText decodes the given response body as UTF-8 text
+// based on the byte order mark or the Content-Type charset.
+// Bodies with unknown charsets are returned as is.
+func decodeText(res *http.Response, body []byte) (string, error) {
+	var enc encoding.Encoding
+	for _, b := range boms {
+		if bytes.HasPrefix(body, b.bom) {
+			enc, _ = htmlindex.Get(b.name)
+			break
+		}
+	}
+
+	if enc == nil {
+		label := mediaCharset(res)
+		if label == "" {
+			return string(body), nil
+		}
+		var err error
+		if enc, err = htmlindex.Get(label); err != nil {
+			return string(body), nil
+		}
+	}
+
+	// UTF-8 bodies are used as is, except the byte order mark
+	if enc == unicode.UTF8 {
+		return string(bytes.TrimPrefix(body, boms[0].bom)), nil
+	}
+	text, _, err := transform.Bytes(unicode.BOMOverride(enc.NewDecoder()), body)
+	if err != nil {
+		return "", fmt.Errorf("Cannot decode body charset: %s", err)
+	}
+	return string(text), nil
+}
+
+// readBodyText reads the response body as UTF-8 text, decoded based on the response charset.
+func readBodyText(res *http.Response) (string, error) {
+	body, err := readBody(res)
+	if err != nil {
+		return "", err
+	}
+	return decodeText(res, body)
+}
+
+// Charset asserts the response Content-Type charset parameter.
+// Charset aliases are supported, such as "latin1" for "ISO-8859-1".
+func Charset(name string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		label := mediaCharset(res)
+		if label == "" {
+			return fmt.Errorf("Charset mismatch: Content-Type has no charset, expected '%s'", name)
+		}
+
+		expected, err := charsetName(name)
+		if err != nil {
+			return fmt.Errorf("Unsupported charset: %s", name)
+		}
+		actual, err := charsetName(label)
+		if err != nil || !strings.EqualFold(actual, expected) {
+			return fmt.Errorf("Charset mismatch: '%s' != '%s'", label, name)
+		}
+		return nil
+	}
+}
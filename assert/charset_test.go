@@ -0,0 +1,55 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func newTextResponse(contentType string, body []byte) *http.Response {
+	return &http.Response{
+		Header: http.Header{"Content-Type": []string{contentType}},
+		Body:   ioutil.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func TestBodyEqualsCharset(t *testing.T) {
+	cases := []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=ISO-8859-1", []byte("Caf\xe9 cr\xe8me")},
+		{"text/plain; charset=latin1", []byte("Caf\xe9 cr\xe8me")},
+		{"text/plain; charset=utf-8", []byte("Café crème")},
+		{"text/plain", []byte("\xef\xbb\xbfCafé crème")},
+		{"text/plain; charset=utf-16", []byte("\xfe\xff\x00C\x00a\x00f\x00\xe9\x00 \x00c\x00r\x00\xe8\x00m\x00e")},
+		{"text/plain", []byte("\xff\xfeC\x00a\x00f\x00\xe9\x00 \x00c\x00r\x00\xe8\x00m\x00e\x00")},
+	}
+	for _, c := range cases {
+		st.Expect(t, BodyEquals("Café crème")(newTextResponse(c.contentType, c.body), nil), nil)
+	}
+}
+
+func TestBodyMatchStringCharset(t *testing.T) {
+	// "日本語" encoded as Shift_JIS
+	res := newTextResponse("text/html; charset=Shift_JIS", []byte("<p>\x93\xfa\x96{\x8c\xea</p>"))
+	st.Expect(t, BodyMatchString("<p>日本語</p>")(res, nil), nil)
+	st.Expect(t, BodyMatchString("日本")(res, nil), nil)
+
+	// Unknown charsets are matched as is
+	res = newTextResponse("text/plain; charset=foo", []byte("hello"))
+	st.Expect(t, BodyMatchString("hello")(res, nil), nil)
+}
+
+func TestCharset(t *testing.T) {
+	res := newTextResponse("text/plain; charset=ISO-8859-1", nil)
+	st.Expect(t, Charset("iso-8859-1")(res, nil), nil)
+	st.Expect(t, Charset("latin1")(res, nil), nil)
+	st.Expect(t, Charset("utf-8")(res, nil).Error(), "Charset mismatch: 'ISO-8859-1' != 'utf-8'")
+	st.Expect(t, Charset("foo")(res, nil).Error(), "Unsupported charset: foo")
+	st.Expect(t, Charset("utf-8")(newTextResponse("text/plain", nil), nil).Error(),
+		"Charset mismatch: Content-Type has no charset, expected 'utf-8'")
+}
@@ -0,0 +1,63 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+// Decoded decodes the response body with the codec registered for the response
+// Content-Type, such as Protocol Buffers, MessagePack, CBOR or YAML,
+// and deeply and strictly compares it with the given structure.
+// Both values are normalized as JSON and compared using the JSON assertion semantics.
+// If the given value is a pointer, such as a proto.Message, the body is decoded
+// into a new value of the same type, otherwise it is decoded into generic values.
+// Strings and byte slices are compared as raw JSON documents.
+// Formats other than JSON require importing their codec subpackage,
+// such as gopkg.in/h2non/baloo.v3/codec/yaml.
+func Decoded(data interface{}) Func {
+	return func(res *http.Response, req *http.Request) error {
+		body, err := readBody(res)
+		if err != nil {
+			return err
+		}
+
+		contentType := res.Header.Get("Content-Type")
+		decoder, err := codec.Lookup(contentType)
+		if err != nil {
+			return err
+		}
+
+		var value interface{}
+		if kind := reflect.TypeOf(data); kind != nil && kind.Kind() == reflect.Ptr {
+			target := reflect.New(kind.Elem()).Interface()
+			if err := decoder.Unmarshal(body, target); err != nil {
+				return fmt.Errorf("failed to decode body as '%s': %s", contentType, err)
+			}
+			value = target
+		} else if err := decoder.Unmarshal(body, &value); err != nil {
+			return fmt.Errorf("failed to decode body as '%s': %s", contentType, err)
+		}
+
+		buf, err := codec.Normalize(value)
+		if err != nil {
+			return err
+		}
+		decoded, err := unmarshal(buf)
+		if err != nil {
+			return err
+		}
+
+		switch data.(type) {
+		case string, []byte:
+			return compare(decoded, data)
+		}
+		expected, err := codec.Normalize(data)
+		if err != nil {
+			return err
+		}
+		return compare(decoded, expected)
+	}
+}
@@ -0,0 +1,64 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	_ "gopkg.in/h2non/baloo.v3/codec/cbor"
+	_ "gopkg.in/h2non/baloo.v3/codec/msgpack"
+	_ "gopkg.in/h2non/baloo.v3/codec/protobuf"
+	_ "gopkg.in/h2non/baloo.v3/codec/yaml"
+)
+
+func decodedResponse(contentType string, body []byte) *http.Response {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	return &http.Response{Header: header, Body: ioutil.NopCloser(bytes.NewReader(body))}
+}
+
+func TestDecodedYAML(t *testing.T) {
+	body := []byte("id: 1\nname: foo\ntags:\n  - bar\n  - baz\n")
+	match := map[string]interface{}{"id": 1, "name": "foo", "tags": []string{"bar", "baz"}}
+	st.Expect(t, Decoded(match)(decodedResponse("application/yaml; charset=utf-8", body), nil), nil)
+	st.Expect(t, Decoded(`{"id":1,"name":"foo","tags":["bar","baz"]}`)(decodedResponse("text/yaml", body), nil), nil)
+	st.Reject(t, Decoded(map[string]interface{}{"id": 2})(decodedResponse("application/yaml", body), nil), nil)
+}
+
+func TestDecodedCBOR(t *testing.T) {
+	// {1: "foo", "bar": [true]}
+	body := []byte{0xa2, 0x01, 0x63, 'f', 'o', 'o', 0x63, 'b', 'a', 'r', 0x81, 0xf5}
+	match := map[string]interface{}{"1": "foo", "bar": []bool{true}}
+	st.Expect(t, Decoded(match)(decodedResponse("application/cbor", body), nil), nil)
+}
+
+func TestDecodedMessagePack(t *testing.T) {
+	// {"foo": "bar"}
+	body := []byte{0x81, 0xa3, 'f', 'o', 'o', 0xa3, 'b', 'a', 'r'}
+	st.Expect(t, Decoded(items{"foo": "bar"})(decodedResponse("application/x-msgpack", body), nil), nil)
+	st.Reject(t, Decoded(items{"foo": "baz"})(decodedResponse("application/x-msgpack", body), nil), nil)
+}
+
+func TestDecodedProtobuf(t *testing.T) {
+	message, _ := structpb.NewStruct(map[string]interface{}{"foo": "bar", "count": 2})
+	body, _ := proto.Marshal(message)
+
+	st.Expect(t, Decoded(message)(decodedResponse("application/x-protobuf", body), nil), nil)
+	// Protocol Buffers require a message to decode into
+	st.Reject(t, Decoded(`{"foo":"bar","count":2}`)(decodedResponse("application/x-protobuf", body), nil), nil)
+	other, _ := structpb.NewStruct(map[string]interface{}{"foo": "baz"})
+	st.Reject(t, Decoded(other)(decodedResponse("application/x-protobuf", body), nil), nil)
+}
+
+func TestDecodedUnsupported(t *testing.T) {
+	err := Decoded(`{}`)(decodedResponse("application/octet-stream", []byte{0x00}), nil)
+	st.Expect(t, err.Error(), "codec: unsupported content type 'application/octet-stream'")
+}
+
+func TestDecodedInvalidBody(t *testing.T) {
+	st.Reject(t, Decoded(`{}`)(decodedResponse("application/cbor", []byte{0xff}), nil), nil)
+}
@@ -0,0 +1,77 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// EncodedLengthKey is the gentleman context key storing the length of the
+// encoded (compressed) response body, used by encoding assertions.
+// This is mostly defined internally by the response decompression middleware.
+const EncodedLengthKey = "$baloo.encodedLength"
+
+// EncodedLength returns the length of the encoded (compressed) response body,
+// if the response body was decompressed.
+func EncodedLength(req *http.Request) (int, bool) {
+	if req == nil {
+		return 0, false
+	}
+	store, ok := req.Context().Value(context.Key).(context.Store)
+	if !ok {
+		return 0, false
+	}
+	length, ok := store[EncodedLengthKey].(int)
+	return length, ok
+}
+
+// ContentEncoding asserts the response Content-Encoding header,
+// such as gzip, br or zstd. Use "identity" to assert uncompressed responses.
+func ContentEncoding(encoding string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		header := res.Header.Get("Content-Encoding")
+		if header == "" {
+			header = "identity"
+		}
+		if !strings.EqualFold(header, encoding) {
+			return fmt.Errorf("Content-Encoding mismatch: '%s' != '%s'", header, encoding)
+		}
+		return nil
+	}
+}
+
+// CompressedLength asserts the length of the compressed response body.
+// The response must be decompressed by the client decompression middleware.
+func CompressedLength(length int) Func {
+	return func(res *http.Response, req *http.Request) error {
+		encoded, ok := EncodedLength(req)
+		if !ok {
+			return fmt.Errorf("Response body was not decompressed: missing Decompress()?")
+		}
+		if encoded != length {
+			return fmt.Errorf("Compressed body length mismatch: %d != %d", encoded, length)
+		}
+		return nil
+	}
+}
+
+// Compressed asserts the compressed response body is smaller than the decompressed one.
+// The response must be decompressed by the client decompression middleware.
+func Compressed() Func {
+	return func(res *http.Response, req *http.Request) error {
+		encoded, ok := EncodedLength(req)
+		if !ok {
+			return fmt.Errorf("Response body was not decompressed: missing Decompress()?")
+		}
+		body, err := readBody(res)
+		if err != nil {
+			return err
+		}
+		if encoded >= len(body) {
+			return fmt.Errorf("Response body is not compressed: %d bytes compressed >= %d bytes decompressed", encoded, len(body))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,58 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// withEncodedLength returns a gentleman context request storing the given encoded length.
+func withEncodedLength(length int) *http.Request {
+	ctx := context.New()
+	ctx.Set(EncodedLengthKey, length)
+	return ctx.Request
+}
+
+func TestEncodedLength(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://foo.com", nil)
+	_, ok := EncodedLength(req)
+	st.Expect(t, ok, false)
+
+	_, ok = EncodedLength(context.New().Request)
+	st.Expect(t, ok, false)
+
+	length, ok := EncodedLength(withEncodedLength(10))
+	st.Expect(t, ok, true)
+	st.Expect(t, length, 10)
+	_, ok = EncodedLength(nil)
+	st.Expect(t, ok, false)
+}
+
+func TestContentEncoding(t *testing.T) {
+	res := &http.Response{Header: http.Header{"Content-Encoding": []string{"gzip"}}}
+	st.Expect(t, ContentEncoding("gzip")(res, nil), nil)
+	st.Expect(t, ContentEncoding("GZIP")(res, nil), nil)
+	st.Expect(t, ContentEncoding("br")(res, nil).Error(), "Content-Encoding mismatch: 'gzip' != 'br'")
+	st.Expect(t, ContentEncoding("identity")(&http.Response{Header: http.Header{}}, nil), nil)
+}
+
+func TestCompressedLength(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://foo.com", nil)
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString("hello world"))}
+	st.Expect(t, CompressedLength(5)(res, withEncodedLength(5)), nil)
+	st.Expect(t, CompressedLength(6)(res, withEncodedLength(5)).Error(), "Compressed body length mismatch: 5 != 6")
+	st.Expect(t, CompressedLength(5)(res, req).Error(), "Response body was not decompressed: missing Decompress()?")
+}
+
+func TestCompressed(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://foo.com", nil)
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString("hello world"))}
+	st.Expect(t, Compressed()(res, withEncodedLength(5)), nil)
+	st.Expect(t, Compressed()(res, withEncodedLength(20)).Error(),
+		"Response body is not compressed: 20 bytes compressed >= 11 bytes decompressed")
+	st.Expect(t, Compressed()(res, req).Error(), "Response body was not decompressed: missing Decompress()?")
+}
@@ -0,0 +1,136 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// graphQLResponse represents the standard GraphQL over HTTP response body.
+type graphQLResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+// graphQLError represents a GraphQL response error.
+type graphQLError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+func (e graphQLError) String() string {
+	if code, ok := e.Extensions["code"]; ok {
+		return fmt.Sprintf("%s (%v)", e.Message, code)
+	}
+	return e.Message
+}
+
+func readGraphQL(res *http.Response) (*graphQLResponse, error) {
+	body, err := readBodyJSON(res)
+	if err != nil {
+		return nil, err
+	}
+	result := &graphQLResponse{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("Invalid GraphQL response: %s", err)
+	}
+	return result, nil
+}
+
+// lookup returns the value at the given path of the decoded JSON value.
+// Paths use dot notation for object fields and brackets for array indexes,
+// such as "users[0].name".
+func lookup(value interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return value, nil
+	}
+
+	current := value
+	for _, part := range strings.Split(strings.Replace(path, "[", ".[", -1), ".") {
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]") {
+			index, err := strconv.Atoi(part[1 : len(part)-1])
+			list, ok := current.([]interface{})
+			if err != nil || !ok || index < 0 || index >= len(list) {
+				return nil, fmt.Errorf("path '%s' not found", path)
+			}
+			current = list[index]
+			continue
+		}
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("path '%s' not found", path)
+		}
+		if current, ok = object[part]; !ok {
+			return nil, fmt.Errorf("path '%s' not found", path)
+		}
+	}
+	return current, nil
+}
+
+// GraphQLData asserts the value at the given path of the GraphQL response data,
+// such as "user.friends[0].name". The value is compared as JSON.
+// Strings are compared as JSON strings, use []byte to compare raw JSON documents.
+func GraphQLData(path string, value interface{}) Func {
+	if s, ok := value.(string); ok {
+		value, _ = json.Marshal(s)
+	}
+	return func(res *http.Response, req *http.Request) error {
+		result, err := readGraphQL(res)
+		if err != nil {
+			return err
+		}
+		data, err := lookup(result.Data, path)
+		if err != nil {
+			return fmt.Errorf("GraphQL data mismatch: %s", err)
+		}
+		if err := compare(data, value); err != nil {
+			return fmt.Errorf("GraphQL data mismatch at '%s': %s", path, err)
+		}
+		return nil
+	}
+}
+
+// GraphQLNoErrors asserts the GraphQL response has no errors.
+func GraphQLNoErrors() Func {
+	return func(res *http.Response, req *http.Request) error {
+		result, err := readGraphQL(res)
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) > 0 {
+			errs := make([]string, len(result.Errors))
+			for i, e := range result.Errors {
+				errs[i] = e.String()
+			}
+			return fmt.Errorf("GraphQL response has errors: %s", strings.Join(errs, "; "))
+		}
+		return nil
+	}
+}
+
+// GraphQLError asserts the GraphQL response has an error
+// with the given extensions code or containing the given message.
+func GraphQLError(match string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		result, err := readGraphQL(res)
+		if err != nil {
+			return err
+		}
+		for _, e := range result.Errors {
+			if code, ok := e.Extensions["code"].(string); ok && code == match {
+				return nil
+			}
+			if strings.Contains(e.Message, match) {
+				return nil
+			}
+		}
+		return fmt.Errorf("GraphQL error not found: '%s' in %d errors", match, len(result.Errors))
+	}
+}
@@ -0,0 +1,69 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+const graphQLBody = `{
+  "data": {"user": {"name": "foo", "friends": [{"name": "bar"}, {"name": "baz"}]}},
+  "errors": [
+    {"message": "Not authorized to access user.email", "path": ["user", "email"], "extensions": {"code": "FORBIDDEN"}}
+  ]
+}`
+
+func newGraphQLResponse(body string) *http.Response {
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+}
+
+func TestLookup(t *testing.T) {
+	data := map[string]interface{}{
+		"users": []interface{}{map[string]interface{}{"name": "foo"}},
+	}
+	value, err := lookup(data, "users[0].name")
+	st.Expect(t, err, nil)
+	st.Expect(t, value, "foo")
+
+	value, err = lookup(data, "$.users[0]")
+	st.Expect(t, err, nil)
+	st.Expect(t, value, map[string]interface{}{"name": "foo"})
+
+	value, _ = lookup(data, "")
+	st.Expect(t, value, data)
+
+	for _, path := range []string{"users[1]", "users.name", "users[0].age", "users[a]"} {
+		_, err = lookup(data, path)
+		st.Expect(t, err.Error(), "path '"+path+"' not found")
+	}
+}
+
+func TestGraphQLData(t *testing.T) {
+	res := newGraphQLResponse(graphQLBody)
+	st.Expect(t, GraphQLData("user.name", "foo")(res, nil), nil)
+	st.Expect(t, GraphQLData("user.friends[1]", map[string]string{"name": "baz"})(res, nil), nil)
+	st.Expect(t, GraphQLData("user.friends[0]", []byte(`{"name": "bar"}`))(res, nil), nil)
+	st.Expect(t, GraphQLData("user.friends[2]", nil)(res, nil).Error(),
+		"GraphQL data mismatch: path 'user.friends[2]' not found")
+	st.Expect(t, strings.HasPrefix(GraphQLData("user.name", "bar")(res, nil).Error(),
+		"GraphQL data mismatch at 'user.name': failed due to JSON mismatch"), true)
+	st.Expect(t, strings.HasPrefix(GraphQLData("user", nil)(newGraphQLResponse("foo"), nil).Error(),
+		"Invalid GraphQL response"), true)
+}
+
+func TestGraphQLNoErrors(t *testing.T) {
+	st.Expect(t, GraphQLNoErrors()(newGraphQLResponse(`{"data": {}}`), nil), nil)
+	st.Expect(t, GraphQLNoErrors()(newGraphQLResponse(graphQLBody), nil).Error(),
+		"GraphQL response has errors: Not authorized to access user.email (FORBIDDEN)")
+}
+
+func TestGraphQLError(t *testing.T) {
+	res := newGraphQLResponse(graphQLBody)
+	st.Expect(t, GraphQLError("FORBIDDEN")(res, nil), nil)
+	st.Expect(t, GraphQLError("Not authorized")(res, nil), nil)
+	st.Expect(t, GraphQLError("NOT_FOUND")(res, nil).Error(), "GraphQL error not found: 'NOT_FOUND' in 1 errors")
+}
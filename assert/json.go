@@ -1,12 +1,12 @@
 package assert
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"reflect"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
 )
 
 func unmarshal(buf []byte) (interface{}, error) {
@@ -18,7 +18,7 @@ func unmarshal(buf []byte) (interface{}, error) {
 }
 
 func unmarshalBody(res *http.Response) (interface{}, error) {
-	body, err := ioutil.ReadAll(res.Body)
+	body, err := readBodyJSON(res)
 	if err != nil {
 		return nil, err
 	}
@@ -33,14 +33,7 @@ func marshal(data interface{}) ([]byte, error) {
 }
 
 func readBodyJSON(res *http.Response) ([]byte, error) {
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return []byte{}, err
-	}
-
-	// Re-fill body reader stream after reading it
-	res.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-	return body, err
+	return httputil.ReadResponseBody(res)
 }
 
 func compare(body interface{}, data interface{}) error {
@@ -82,8 +75,9 @@ func compare(body interface{}, data interface{}) error {
 		return err
 	}
 
-	// Compare values so order of keys in maps does not influence the result
-	if !reflect.DeepEqual(bodyValue, matchValue) {
+	// Compare values so order of keys in maps does not influence the result,
+	// evaluating the placeholder matchers in the expected value
+	if !equal(bodyValue, matchValue) {
 		return fmt.Errorf("failed due to JSON mismatch:\n\thave: %#v\n\twant: %#v", string(bodyBytes), string(matchBytes))
 	}
 
@@ -92,6 +86,8 @@ func compare(body interface{}, data interface{}) error {
 
 // JSON deeply and strictly compares the JSON
 // response body with the given JSON structure.
+// Placeholder matchers, such as AnyString() or UUID(), can be used
+// inside the structure to match dynamic values.
 func JSON(data interface{}) Func {
 	return func(res *http.Response, req *http.Request) error {
 		// Read and unmarshal response body as JSON
@@ -103,3 +99,39 @@ func JSON(data interface{}) Func {
 		return compare(body, data)
 	}
 }
+
+// contains reports whether the given decoded JSON value contains the expected one.
+// Objects match if every expected field is contained in the value,
+// while arrays, numbers, strings and booleans must be equal.
+func contains(value interface{}, expected interface{}) bool {
+	if match, ok := placeholder(expected); ok {
+		return match(value)
+	}
+
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, field := range expected {
+			if v, ok := object[key]; !ok || !contains(v, field) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok || len(list) != len(expected) {
+			return false
+		}
+		for i, item := range expected {
+			if !contains(list[i], item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(value, expected)
+	}
+}
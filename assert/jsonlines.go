@@ -0,0 +1,158 @@
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// JSONLinesMatcher creates the assertion functions of a JSON Lines (NDJSON) response stream.
+// The record function is called for every record while streaming, and the end
+// function is called once the stream ends with the number of records read.
+// Both functions are optional.
+type JSONLinesMatcher func() (record func(index int, data interface{}) error, end func(count int) error)
+
+// JSONLines asserts a newline-delimited JSON response body,
+// evaluating the given matchers while streaming the records.
+// Blank lines are ignored. The response body is consumed and not re-filled.
+func JSONLines(matchers ...JSONLinesMatcher) Func {
+	return func(res *http.Response, req *http.Request) error {
+		defer func() {
+			res.Body.Close()
+			res.Body = ioutil.NopCloser(bytes.NewReader(nil))
+		}()
+
+		records := make([]func(int, interface{}) error, 0, len(matchers))
+		ends := make([]func(int) error, 0, len(matchers))
+		for _, matcher := range matchers {
+			record, end := matcher()
+			if record != nil {
+				records = append(records, record)
+			}
+			if end != nil {
+				ends = append(ends, end)
+			}
+		}
+
+		reader := bufio.NewReader(res.Body)
+		count := 0
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				data, jsonErr := unmarshal(line)
+				if jsonErr != nil {
+					return fmt.Errorf("JSON Lines record #%d is invalid: %s", count, jsonErr)
+				}
+				for _, record := range records {
+					if err := record(count, data); err != nil {
+						return fmt.Errorf("JSON Lines record #%d mismatch: %s", count, err)
+					}
+				}
+				count++
+			}
+
+			if err == io.EOF {
+				break
+			}
+		}
+
+		for _, end := range ends {
+			if err := end(count); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// LinesCount asserts the number of JSON Lines records.
+func LinesCount(count int) JSONLinesMatcher {
+	return func() (func(int, interface{}) error, func(int) error) {
+		return nil, func(n int) error {
+			if n != count {
+				return fmt.Errorf("JSON Lines count mismatch: %d != %d", n, count)
+			}
+			return nil
+		}
+	}
+}
+
+// LinesSchema validates every JSON Lines record against the given JSON schema.
+func LinesSchema(schema string) JSONLinesMatcher {
+	return func() (func(int, interface{}) error, func(int) error) {
+		var compiled *gojsonschema.Schema
+		return func(index int, data interface{}) error {
+			if compiled == nil {
+				var err error
+				if compiled, err = gojsonschema.NewSchema(schemaLoader(schema)); err != nil {
+					return err
+				}
+			}
+
+			result, err := compiled.Validate(gojsonschema.NewGoLoader(data))
+			if err != nil {
+				return err
+			}
+			if !result.Valid() {
+				msg := "JSON document is not valid for the following reasons:\n"
+				for _, detail := range result.Errors() {
+					msg += fmt.Sprintf("\t- %s\n", detail)
+				}
+				return errors.New(msg)
+			}
+			return nil
+		}, nil
+	}
+}
+
+// LineAt deeply and strictly compares the JSON Lines record
+// at the given zero-based index with the given JSON structure.
+func LineAt(index int, data interface{}) JSONLinesMatcher {
+	return func() (func(int, interface{}) error, func(int) error) {
+		record := func(i int, value interface{}) error {
+			if i != index {
+				return nil
+			}
+			return compare(value, data)
+		}
+		end := func(count int) error {
+			if count <= index {
+				return fmt.Errorf("JSON Lines record #%d not found: received %d records", index, count)
+			}
+			return nil
+		}
+		return record, end
+	}
+}
+
+// LineAny asserts at least one JSON Lines record is
+// deeply and strictly equal to the given JSON structure.
+func LineAny(data interface{}) JSONLinesMatcher {
+	return func() (func(int, interface{}) error, func(int) error) {
+		found := false
+		record := func(i int, value interface{}) error {
+			if !found && compare(value, data) == nil {
+				found = true
+			}
+			return nil
+		}
+		end := func(count int) error {
+			if !found {
+				buf, _ := marshal(data)
+				return fmt.Errorf("JSON Lines record not found in %d records: %s", count, buf)
+			}
+			return nil
+		}
+		return record, end
+	}
+}
@@ -0,0 +1,75 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+const jsonLines = `{"id":1,"name":"foo"}
+{"id":2,"name":"bar"}
+
+{"id":3,"name":"baz"}
+`
+
+const jsonLinesSchema = `{
+  "type": "object",
+  "required": ["id", "name"],
+  "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+}`
+
+func newLinesResponse(body string) *http.Response {
+	return &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+}
+
+func TestJSONLines(t *testing.T) {
+	res := newLinesResponse(jsonLines)
+	st.Expect(t, JSONLines(
+		LinesCount(3),
+		LinesSchema(jsonLinesSchema),
+		LineAt(0, map[string]interface{}{"id": 1, "name": "foo"}),
+		LineAt(2, `{"name":"baz","id":3}`),
+		LineAny(map[string]interface{}{"id": 2, "name": "bar"}),
+	)(res, nil), nil)
+
+	// Body is consumed
+	body, _ := ioutil.ReadAll(res.Body)
+	st.Expect(t, len(body), 0)
+}
+
+func TestJSONLinesNoTrailingNewline(t *testing.T) {
+	res := newLinesResponse(`{"id":1}` + "\r\n" + `{"id":2}`)
+	st.Expect(t, JSONLines(LinesCount(2), LineAt(1, `{"id":2}`))(res, nil), nil)
+}
+
+func TestJSONLinesInvalid(t *testing.T) {
+	err := JSONLines()(newLinesResponse("{\"id\":1}\nfoo\n"), nil)
+	st.Expect(t, strings.HasPrefix(err.Error(), "JSON Lines record #1 is invalid"), true)
+}
+
+func TestLinesCount(t *testing.T) {
+	err := JSONLines(LinesCount(2))(newLinesResponse(jsonLines), nil)
+	st.Expect(t, err.Error(), "JSON Lines count mismatch: 3 != 2")
+}
+
+func TestLinesSchema(t *testing.T) {
+	err := JSONLines(LinesSchema(jsonLinesSchema))(newLinesResponse("{\"id\":1,\"name\":\"foo\"}\n{\"id\":\"2\"}"), nil)
+	st.Expect(t, strings.HasPrefix(err.Error(), "JSON Lines record #1 mismatch: JSON document is not valid"), true)
+}
+
+func TestLineAt(t *testing.T) {
+	err := JSONLines(LineAt(1, `{"id":1,"name":"foo"}`))(newLinesResponse(jsonLines), nil)
+	st.Expect(t, strings.HasPrefix(err.Error(), "JSON Lines record #1 mismatch: failed due to JSON mismatch"), true)
+
+	err = JSONLines(LineAt(3, `{}`))(newLinesResponse(jsonLines), nil)
+	st.Expect(t, err.Error(), "JSON Lines record #3 not found: received 3 records")
+}
+
+func TestLineAny(t *testing.T) {
+	err := JSONLines(LineAny(map[string]int{"id": 4}))(newLinesResponse(jsonLines), nil)
+	st.Expect(t, err.Error(), `JSON Lines record not found in 3 records: {"id":4}`)
+}
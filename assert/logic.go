@@ -0,0 +1,72 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Not negates the given assertion function,
+// failing if the assertion succeeds.
+func Not(fn Func) Func {
+	return func(res *http.Response, req *http.Request) error {
+		if err := fn(res, req); err != nil {
+			return nil
+		}
+		return errors.New("negated assertion should fail, but it succeeded")
+	}
+}
+
+// AnyOf asserts that at least one of the given assertion functions succeeds.
+// If all of them fail, the returned error explains every failed branch.
+func AnyOf(fns ...Func) Func {
+	return func(res *http.Response, req *http.Request) error {
+		errs := make([]error, 0, len(fns))
+		for _, fn := range fns {
+			err := fn(res, req)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return branchesError("none of the assertions succeeded", errs)
+	}
+}
+
+// AllOf asserts that all the given assertion functions succeed.
+// If any of them fails, the returned error explains every failed branch.
+func AllOf(fns ...Func) Func {
+	return func(res *http.Response, req *http.Request) error {
+		var errs []error
+		for _, fn := range fns {
+			if err := fn(res, req); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		return branchesError("not all the assertions succeeded", errs)
+	}
+}
+
+// When runs the given assertion function only if
+// the condition assertion function succeeds.
+func When(cond Func, fn Func) Func {
+	return func(res *http.Response, req *http.Request) error {
+		if err := cond(res, req); err != nil {
+			return nil
+		}
+		return fn(res, req)
+	}
+}
+
+func branchesError(msg string, errs []error) error {
+	msg += ":\n"
+	for i, err := range errs {
+		detail := strings.Replace(err.Error(), "\n", "\n\t  ", -1)
+		msg += fmt.Sprintf("\t- #%d: %s\n", i+1, detail)
+	}
+	return errors.New(msg)
+}
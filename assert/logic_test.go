@@ -0,0 +1,49 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestNot(t *testing.T) {
+	res := &http.Response{StatusCode: 200}
+	st.Expect(t, Not(StatusRange(400, 599))(res, nil), nil)
+	st.Reject(t, Not(StatusRange(200, 299))(res, nil), nil)
+}
+
+func TestAnyOf(t *testing.T) {
+	body := ioutil.NopCloser(bytes.NewBufferString(""))
+	res := &http.Response{StatusCode: 204, Body: body, Header: http.Header{}}
+	fn := AnyOf(
+		AllOf(StatusRange(200, 200), JSON(map[string]string{"foo": "bar"})),
+		AllOf(StatusRange(204, 204), BodyLength(0)),
+	)
+	st.Expect(t, fn(res, nil), nil)
+
+	res.StatusCode = 404
+	err := fn(res, nil)
+	st.Reject(t, err, nil)
+	st.Expect(t, strings.Contains(err.Error(), "#1"), true)
+	st.Expect(t, strings.Contains(err.Error(), "#2"), true)
+}
+
+func TestAllOf(t *testing.T) {
+	res := &http.Response{StatusCode: 200, Header: http.Header{"Foo": []string{"bar"}}}
+	st.Expect(t, AllOf(StatusRange(200, 299), HeaderEquals("foo", "bar"))(res, nil), nil)
+
+	err := AllOf(StatusRange(400, 499), HeaderEquals("foo", "baz"))(res, nil)
+	st.Reject(t, err, nil)
+	st.Expect(t, strings.Contains(err.Error(), "Status code"), true)
+	st.Expect(t, strings.Contains(err.Error(), "Header mismatch"), true)
+}
+
+func TestWhen(t *testing.T) {
+	res := &http.Response{StatusCode: 204, Header: http.Header{}}
+	st.Expect(t, When(StatusRange(200, 200), HeaderPresent("foo"))(res, nil), nil)
+	st.Reject(t, When(StatusRange(204, 204), HeaderPresent("foo"))(res, nil), nil)
+}
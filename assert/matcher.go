@@ -0,0 +1,190 @@
+package assert
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// matcherKey is the JSON object field used to represent placeholder matchers.
+const matcherKey = "$baloo"
+
+// uuidPattern matches UUIDs in their canonical textual representation.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Matcher represents a placeholder matching dynamic values, such as
+// generated IDs or timestamps, inside expected JSON structures:
+//
+//	JSON(map[string]interface{}{"id": UUID(), "name": "foo", "createdAt": RFC3339()})
+//
+// The rest of the document is still compared strictly.
+type Matcher struct {
+	name string
+	args []interface{}
+}
+
+// MarshalJSON implements the json.Marshaler interface,
+// serializing the matcher as a placeholder object.
+func (m Matcher) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{matcherKey: m.name, "args": m.args})
+}
+
+// AnyString matches any string value.
+func AnyString() Matcher {
+	return Matcher{name: "anyString"}
+}
+
+// Regex matches string values matching the given regular expression.
+// It panics if the expression cannot be compiled.
+func Regex(pattern string) Matcher {
+	compileRegex(pattern)
+	return Matcher{name: "regex", args: []interface{}{pattern}}
+}
+
+// regexCache stores the compiled regex matcher expressions by pattern,
+// so they are compiled once instead of on every comparison.
+var regexCache sync.Map
+
+// compileRegex returns the compiled regular expression of the given pattern,
+// panicking if the expression cannot be compiled.
+func compileRegex(pattern string) *regexp.Regexp {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	regexCache.Store(pattern, re)
+	return re
+}
+
+// UUID matches string values representing a UUID, such as "f47ac10b-58cc-4372-a567-0e02b2c3d479".
+func UUID() Matcher {
+	return Matcher{name: "uuid"}
+}
+
+// RFC3339 matches string values representing an RFC 3339 timestamp, such as "2006-01-02T15:04:05Z".
+func RFC3339() Matcher {
+	return Matcher{name: "rfc3339"}
+}
+
+// Between matches numbers within the given inclusive range.
+func Between(min, max float64) Matcher {
+	return Matcher{name: "between", args: []interface{}{min, max}}
+}
+
+// Len matches strings, arrays and objects of the given length.
+// String length is measured in characters.
+func Len(length int) Matcher {
+	return Matcher{name: "len", args: []interface{}{length}}
+}
+
+// matchers stores the placeholder matching functions by name.
+// Arguments are received as decoded JSON values.
+var matchers = map[string]func(value interface{}, args []interface{}) bool{
+	"anyString": func(value interface{}, args []interface{}) bool {
+		_, ok := value.(string)
+		return ok
+	},
+	"regex": func(value interface{}, args []interface{}) bool {
+		str, ok := value.(string)
+		if !ok || len(args) != 1 {
+			return false
+		}
+		pattern, _ := args[0].(string)
+		return compileRegex(pattern).MatchString(str)
+	},
+	"uuid": func(value interface{}, args []interface{}) bool {
+		str, ok := value.(string)
+		return ok && uuidPattern.MatchString(str)
+	},
+	"rfc3339": func(value interface{}, args []interface{}) bool {
+		str, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339Nano, str)
+		return err == nil
+	},
+	"between": func(value interface{}, args []interface{}) bool {
+		number, ok := value.(float64)
+		if !ok || len(args) != 2 {
+			return false
+		}
+		min, _ := args[0].(float64)
+		max, _ := args[1].(float64)
+		return number >= min && number <= max
+	},
+	"len": func(value interface{}, args []interface{}) bool {
+		if len(args) != 1 {
+			return false
+		}
+		length, _ := args[0].(float64)
+		switch value := value.(type) {
+		case string:
+			return float64(utf8.RuneCountInString(value)) == length
+		case []interface{}:
+			return float64(len(value)) == length
+		case map[string]interface{}:
+			return float64(len(value)) == length
+		}
+		return false
+	},
+}
+
+// placeholder returns the matching function of the given
+// decoded JSON value if it represents a placeholder matcher.
+func placeholder(expected interface{}) (func(interface{}) bool, bool) {
+	object, ok := expected.(map[string]interface{})
+	if !ok || len(object) != 2 {
+		return nil, false
+	}
+	name, ok := object[matcherKey].(string)
+	if !ok {
+		return nil, false
+	}
+	match, ok := matchers[name]
+	if !ok {
+		return nil, false
+	}
+	args, _ := object["args"].([]interface{})
+	return func(value interface{}) bool {
+		return match(value, args)
+	}, true
+}
+
+// equal deeply compares the given decoded JSON values,
+// evaluating the placeholder matchers in the expected one.
+func equal(value interface{}, expected interface{}) bool {
+	if match, ok := placeholder(expected); ok {
+		return match(value)
+	}
+
+	switch expected := expected.(type) {
+	case map[string]interface{}:
+		object, ok := value.(map[string]interface{})
+		if !ok || len(object) != len(expected) {
+			return false
+		}
+		for key, field := range expected {
+			if v, ok := object[key]; !ok || !equal(v, field) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		list, ok := value.([]interface{})
+		if !ok || len(list) != len(expected) {
+			return false
+		}
+		for i, item := range expected {
+			if !equal(list[i], item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(value, expected)
+	}
+}
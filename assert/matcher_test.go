@@ -0,0 +1,94 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestJSONMatchers(t *testing.T) {
+	body := `{
+		"id": "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"name": "foo",
+		"code": "AB-123",
+		"createdAt": "2024-02-29T10:20:30.123Z",
+		"score": 7.5,
+		"tags": ["a", "b", "c"],
+		"meta": {"role": "admin"}
+	}`
+	match := map[string]interface{}{
+		"id":        UUID(),
+		"name":      AnyString(),
+		"code":      Regex(`^[A-Z]{2}-\d+$`),
+		"createdAt": RFC3339(),
+		"score":     Between(0, 10),
+		"tags":      Len(3),
+		"meta":      map[string]interface{}{"role": Len(5)},
+	}
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+	st.Expect(t, JSON(match)(res, nil), nil)
+}
+
+func TestJSONMatchersMismatch(t *testing.T) {
+	cases := []struct {
+		body  string
+		match interface{}
+	}{
+		{`{"id": 1}`, map[string]interface{}{"id": AnyString()}},
+		{`{"id": "foo"}`, map[string]interface{}{"id": UUID()}},
+		{`{"id": "foo"}`, map[string]interface{}{"id": Regex("^bar")}},
+		{`{"at": "2024-02-30"}`, map[string]interface{}{"at": RFC3339()}},
+		{`{"n": 11}`, map[string]interface{}{"n": Between(0, 10)}},
+		{`{"n": "5"}`, map[string]interface{}{"n": Between(0, 10)}},
+		{`{"list": [1, 2]}`, map[string]interface{}{"list": Len(3)}},
+		{`{"s": "héllo"}`, map[string]interface{}{"s": Len(6)}},
+		// The rest of the document is still compared strictly
+		{`{"id": "foo", "name": "bar"}`, map[string]interface{}{"id": AnyString(), "name": "baz"}},
+		{`{"id": "foo", "name": "bar"}`, map[string]interface{}{"id": AnyString()}},
+	}
+	for _, c := range cases {
+		res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(c.body))}
+		st.Reject(t, JSON(c.match)(res, nil), nil)
+	}
+}
+
+func TestRegexInvalid(t *testing.T) {
+	defer func() {
+		st.Reject(t, recover(), nil)
+	}()
+	Regex("(")
+	t.Fatal("expected invalid regex to panic")
+}
+
+func TestRegexCache(t *testing.T) {
+	Regex(`^\d+$`)
+	re, ok := regexCache.Load(`^\d+$`)
+	st.Expect(t, ok, true)
+	st.Expect(t, compileRegex(`^\d+$`), re)
+}
+
+func TestJSONMatchersTopLevel(t *testing.T) {
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`[1, 2]`))}
+	st.Expect(t, JSON(Len(2))(res, nil), nil)
+
+	res = &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`"héllo"`))}
+	st.Expect(t, JSON(Len(5))(res, nil), nil)
+}
+
+func TestJSONMatchersStruct(t *testing.T) {
+	type user struct {
+		ID   interface{} `json:"id"`
+		Name string      `json:"name"`
+	}
+	res := &http.Response{Body: ioutil.NopCloser(bytes.NewBufferString(`{"id": "f47ac10b-58cc-4372-a567-0e02b2c3d479", "name": "foo"}`))}
+	st.Expect(t, JSON(user{ID: UUID(), Name: "foo"})(res, nil), nil)
+}
+
+func TestContainsMatchers(t *testing.T) {
+	value := map[string]interface{}{"id": "foo", "count": 2.0}
+	st.Expect(t, contains(value, map[string]interface{}{"id": map[string]interface{}{matcherKey: "anyString", "args": nil}}), true)
+	st.Expect(t, contains(value, map[string]interface{}{"count": map[string]interface{}{matcherKey: "between", "args": []interface{}{3.0, 4.0}}}), false)
+}
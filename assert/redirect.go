@@ -0,0 +1,72 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redirects returns the redirect responses followed to obtain the given
+// final response, in order. The request of each redirect response
+// is the request originating the redirect.
+func Redirects(res *http.Response) []*http.Response {
+	var chain []*http.Response
+	if res == nil || res.Request == nil {
+		return chain
+	}
+	for hop := res.Request.Response; hop != nil; {
+		chain = append([]*http.Response{hop}, chain...)
+		if hop.Request == nil {
+			break
+		}
+		hop = hop.Request.Response
+	}
+	return chain
+}
+
+// redirectURLs returns the URLs requested after each redirect.
+func redirectURLs(res *http.Response) []*url.URL {
+	var urls []*url.URL
+	req := res.Request
+	for req != nil && req.Response != nil {
+		urls = append([]*url.URL{req.URL}, urls...)
+		req = req.Response.Request
+	}
+	return urls
+}
+
+// RedirectCount asserts the number of redirects followed.
+func RedirectCount(count int) Func {
+	return func(res *http.Response, req *http.Request) error {
+		if n := len(Redirects(res)); n != count {
+			return fmt.Errorf("Redirect count mismatch: %d != %d", n, count)
+		}
+		return nil
+	}
+}
+
+// RedirectChain asserts the URLs followed by redirects, in order.
+// URLs starting with "/" are compared with the redirect URL path and query.
+func RedirectChain(urls ...string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		chain := make([]string, 0, len(urls))
+		for i, u := range redirectURLs(res) {
+			if i < len(urls) && strings.HasPrefix(urls[i], "/") {
+				chain = append(chain, u.RequestURI())
+			} else {
+				chain = append(chain, u.String())
+			}
+		}
+
+		if len(chain) != len(urls) {
+			return fmt.Errorf("Redirect chain mismatch: %v != %v", chain, urls)
+		}
+		for i, expected := range urls {
+			if chain[i] != expected {
+				return fmt.Errorf("Redirect chain mismatch at #%d: '%s' != '%s'", i+1, chain[i], expected)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,54 @@
+package assert
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func newRedirectResponse(urls ...string) *http.Response {
+	var prev *http.Response
+	for i, uri := range urls {
+		u, _ := url.Parse(uri)
+		req := &http.Request{URL: u, Response: prev}
+		if i == len(urls)-1 {
+			return &http.Response{StatusCode: 200, Request: req}
+		}
+		prev = &http.Response{StatusCode: 302, Request: req}
+	}
+	return nil
+}
+
+func TestRedirects(t *testing.T) {
+	res := newRedirectResponse("http://foo.com/a", "http://foo.com/b", "http://bar.com/c?d=e")
+	chain := Redirects(res)
+	st.Expect(t, len(chain), 2)
+	st.Expect(t, chain[0].Request.URL.Path, "/a")
+	st.Expect(t, chain[1].Request.URL.Path, "/b")
+	st.Expect(t, len(Redirects(&http.Response{})), 0)
+}
+
+func TestRedirectCount(t *testing.T) {
+	res := newRedirectResponse("http://foo.com/a", "http://foo.com/b", "http://bar.com/c?d=e")
+	st.Expect(t, RedirectCount(2)(res, nil), nil)
+	st.Expect(t, RedirectCount(1)(res, nil).Error(), "Redirect count mismatch: 2 != 1")
+}
+
+func TestRedirectChain(t *testing.T) {
+	res := newRedirectResponse("http://foo.com/a", "http://foo.com/b", "http://bar.com/c?d=e")
+	st.Expect(t, RedirectChain("/b", "/c?d=e")(res, nil), nil)
+	st.Expect(t, RedirectChain("http://foo.com/b", "http://bar.com/c?d=e")(res, nil), nil)
+	st.Expect(t, RedirectChain("/b", "http://foo.com/c?d=e")(res, nil).Error(),
+		"Redirect chain mismatch at #2: 'http://bar.com/c?d=e' != 'http://foo.com/c?d=e'")
+	st.Expect(t, RedirectChain("/b")(res, nil).Error(), "Redirect chain mismatch: [/b http://bar.com/c?d=e] != [/b]")
+}
+
+func TestStatusRedirect(t *testing.T) {
+	for _, code := range []int{301, 302, 303, 307, 308} {
+		st.Expect(t, StatusRedirect()(&http.Response{StatusCode: code}, nil), nil)
+	}
+	st.Expect(t, StatusRedirect()(&http.Response{StatusCode: 304}, nil).Error(),
+		"Status code is not a redirect: 304 not in [301 302 303 307 308]")
+}
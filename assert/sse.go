@@ -0,0 +1,271 @@
+package assert
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEEvent represents a Server-Sent Event received in a text/event-stream response.
+type SSEEvent struct {
+	// Event stores the event type name. Defaults to "message".
+	Event string
+	// ID stores the last event ID.
+	ID string
+	// Data stores the event data. Multiple data lines are joined by a line feed.
+	Data string
+	// Retry stores the reconnection time in milliseconds, if defined by the event.
+	Retry int
+}
+
+// SSEMatcher represents the required interface for Server-Sent Event assertion functions.
+type SSEMatcher func(*SSEEvent) error
+
+// sseParser parses Server-Sent Events line by line,
+// as defined by the WHATWG HTML specification.
+type sseParser struct {
+	reader *bufio.Reader
+	lastID string
+}
+
+// next returns the next event in the stream, or io.EOF if the stream ended.
+func (p *sseParser) next() (*SSEEvent, error) {
+	event := &SSEEvent{}
+	var data []string
+	var hasData bool
+
+	for {
+		line, err := p.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// Blank lines dispatch the event, if any data is present
+		if line == "" {
+			if !hasData {
+				event = &SSEEvent{Retry: event.Retry}
+				continue
+			}
+			if event.Event == "" {
+				event.Event = "message"
+			}
+			event.ID = p.lastID
+			event.Data = strings.Join(data, "\n")
+			return event, nil
+		}
+
+		// Comment lines are ignored
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				p.lastID = value
+			}
+		case "retry":
+			if retry, err := strconv.Atoi(value); err == nil && retry >= 0 {
+				event.Retry = retry
+			}
+		}
+	}
+}
+
+// ReadEvents incrementally reads up to max Server-Sent Events from the response body,
+// stopping once the stream ends, the max number of events is reached or the timeout expires.
+// Zero max and timeout values disable the limit.
+// The consumed body is closed and re-filled to be read by subsequent assertions.
+func ReadEvents(res *http.Response, max int, timeout time.Duration) ([]*SSEEvent, error) {
+	raw := &syncBuffer{}
+	parser := &sseParser{reader: bufio.NewReader(io.TeeReader(res.Body, raw))}
+
+	events := make(chan *SSEEvent)
+	done := make(chan error, 1)
+	stop := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			event, err := parser.next()
+			if err != nil {
+				done <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-stop:
+				done <- nil
+				return
+			}
+		}
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var list []*SSEEvent
+	stopped := false
+loop:
+	for {
+		if max > 0 && len(list) >= max {
+			stopped = true
+			break
+		}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				break loop
+			}
+			list = append(list, event)
+		case <-expired:
+			stopped = true
+			break loop
+		}
+	}
+
+	// Close the stream to stop reading
+	close(stop)
+	res.Body.Close()
+
+	// Read errors are expected if the stream was stopped
+	var err error
+	if !stopped {
+		if err = <-done; err == io.EOF {
+			err = nil
+		}
+	}
+
+	// Re-fill body reader stream with the consumed data
+	res.Body = ioutil.NopCloser(bytes.NewReader(raw.Bytes()))
+	return list, err
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+// Bytes returns a copy of the buffered data.
+func (b *syncBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]byte{}, b.buf.Bytes()...)
+}
+
+// Events asserts the sequence of Server-Sent Events in the response stream.
+// Each list of matchers asserts the event at the same position.
+// See ReadEvents for the max and timeout semantics.
+func Events(max int, timeout time.Duration, events ...[]SSEMatcher) Func {
+	return func(res *http.Response, req *http.Request) error {
+		list, err := ReadEvents(res, max, timeout)
+		if err != nil {
+			return err
+		}
+		if len(list) < len(events) {
+			return fmt.Errorf("SSE events mismatch: received %d events, expected at least %d", len(list), len(events))
+		}
+		for i, matchers := range events {
+			for _, matcher := range matchers {
+				if err := matcher(list[i]); err != nil {
+					return fmt.Errorf("SSE event #%d mismatch: %s", i+1, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// EventCount asserts the number of Server-Sent Events received in the response stream.
+// See ReadEvents for the max and timeout semantics.
+func EventCount(count, max int, timeout time.Duration) Func {
+	return func(res *http.Response, req *http.Request) error {
+		list, err := ReadEvents(res, max, timeout)
+		if err != nil {
+			return err
+		}
+		if len(list) != count {
+			return fmt.Errorf("SSE event count mismatch: %d != %d", len(list), count)
+		}
+		return nil
+	}
+}
+
+// EventName asserts the Server-Sent Event type name.
+func EventName(name string) SSEMatcher {
+	return func(event *SSEEvent) error {
+		if event.Event != name {
+			return fmt.Errorf("event name '%s' != '%s'", event.Event, name)
+		}
+		return nil
+	}
+}
+
+// EventID asserts the Server-Sent Event ID.
+func EventID(id string) SSEMatcher {
+	return func(event *SSEEvent) error {
+		if event.ID != id {
+			return fmt.Errorf("event id '%s' != '%s'", event.ID, id)
+		}
+		return nil
+	}
+}
+
+// EventData asserts the Server-Sent Event data using strict comparison.
+func EventData(data string) SSEMatcher {
+	return func(event *SSEEvent) error {
+		if event.Data != data {
+			return fmt.Errorf("event data %#v != %#v", event.Data, data)
+		}
+		return nil
+	}
+}
+
+// EventJSON deeply compares the Server-Sent Event data as JSON with the given JSON structure.
+func EventJSON(data interface{}) SSEMatcher {
+	return func(event *SSEEvent) error {
+		body, err := unmarshal([]byte(event.Data))
+		if err != nil {
+			return err
+		}
+		return compare(body, data)
+	}
+}
+
+// EventRetry asserts the Server-Sent Event reconnection time in milliseconds.
+func EventRetry(retry int) SSEMatcher {
+	return func(event *SSEEvent) error {
+		if event.Retry != retry {
+			return fmt.Errorf("event retry %d != %d", event.Retry, retry)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,96 @@
+package assert
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+const sseStream = `: comment
+
+retry: 3000
+data: hello
+
+event: update
+id: 1
+data: {"id": 1,
+data:  "name": "foo"}
+
+event: update
+data:no space
+
+data: trailing`
+
+func newStreamResponse(body io.Reader) *http.Response {
+	return &http.Response{
+		Header: http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:   ioutil.NopCloser(body),
+	}
+}
+
+func TestReadEvents(t *testing.T) {
+	res := newStreamResponse(strings.NewReader(sseStream))
+	events, err := ReadEvents(res, 0, 0)
+	st.Expect(t, err, nil)
+	st.Expect(t, len(events), 3)
+	st.Expect(t, *events[0], SSEEvent{Event: "message", Data: "hello", Retry: 3000})
+	st.Expect(t, *events[1], SSEEvent{Event: "update", ID: "1", Data: "{\"id\": 1,\n \"name\": \"foo\"}"})
+	st.Expect(t, *events[2], SSEEvent{Event: "update", ID: "1", Data: "no space"})
+
+	// Consumed body is re-filled
+	body, _ := ioutil.ReadAll(res.Body)
+	st.Expect(t, string(body), sseStream)
+}
+
+func TestReadEventsMax(t *testing.T) {
+	res := newStreamResponse(strings.NewReader(sseStream))
+	events, err := ReadEvents(res, 2, 0)
+	st.Expect(t, err, nil)
+	st.Expect(t, len(events), 2)
+}
+
+func TestReadEventsTimeout(t *testing.T) {
+	reader, writer := io.Pipe()
+	go writer.Write([]byte("data: foo\n\ndata: bar\n\n"))
+
+	start := time.Now()
+	events, err := ReadEvents(newStreamResponse(reader), 0, 50*time.Millisecond)
+	st.Expect(t, err, nil)
+	st.Expect(t, len(events), 2)
+	st.Expect(t, time.Since(start) >= 50*time.Millisecond, true)
+}
+
+func TestEvents(t *testing.T) {
+	res := newStreamResponse(strings.NewReader(sseStream))
+	st.Expect(t, Events(0, 0,
+		[]SSEMatcher{EventName("message"), EventData("hello"), EventRetry(3000)},
+		[]SSEMatcher{EventName("update"), EventID("1"), EventJSON(map[string]interface{}{"id": 1, "name": "foo"})},
+	)(res, nil), nil)
+
+	st.Expect(t, Events(0, 0, []SSEMatcher{EventName("update")})(res, nil).Error(),
+		"SSE event #1 mismatch: event name 'message' != 'update'")
+	st.Expect(t, Events(1, 0, nil, nil)(res, nil).Error(),
+		"SSE events mismatch: received 1 events, expected at least 2")
+}
+
+func TestEventCount(t *testing.T) {
+	res := newStreamResponse(strings.NewReader(sseStream))
+	st.Expect(t, EventCount(3, 0, 0)(res, nil), nil)
+	st.Expect(t, EventCount(2, 0, 0)(res, nil).Error(), "SSE event count mismatch: 3 != 2")
+}
+
+func TestEventMatchers(t *testing.T) {
+	event := &SSEEvent{Event: "message", ID: "1", Data: `{"foo":"bar"}`, Retry: 10}
+	st.Expect(t, EventName("message")(event), nil)
+	st.Expect(t, EventID("2")(event).Error(), "event id '1' != '2'")
+	st.Expect(t, EventData(`{"foo":"bar"}`)(event), nil)
+	st.Expect(t, EventData("foo")(event).Error(), `event data "{\"foo\":\"bar\"}" != "foo"`)
+	st.Expect(t, EventJSON(map[string]string{"foo": "bar"})(event), nil)
+	st.Reject(t, EventJSON(map[string]string{"foo": "baz"})(event), nil)
+	st.Expect(t, EventRetry(20)(event).Error(), "event retry 10 != 20")
+}
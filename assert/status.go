@@ -29,10 +29,16 @@ func StatusClientError() Func {
 	return StatusRange(400, 499)
 }
 
-// StatusRedirect asserts the response status code as
-// server redirect status (= 301 || = 302).
+// StatusRedirect asserts the response status code as server
+// redirect status (301, 302, 303, 307 or 308).
 func StatusRedirect() Func {
-	return StatusRange(301, 302)
+	return func(res *http.Response, req *http.Request) error {
+		switch res.StatusCode {
+		case 301, 302, 303, 307, 308:
+			return nil
+		}
+		return fmt.Errorf("Status code is not a redirect: %d not in [301 302 303 307 308]", res.StatusCode)
+	}
 }
 
 // StatusEqual asserts the response status code with the given number.
@@ -0,0 +1,144 @@
+package assert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// tlsVersions stores the TLS version names by protocol version.
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// TLSVersionName returns the human-friendly name of the given TLS version.
+func TLSVersionName(version uint16) string {
+	if name, ok := tlsVersions[version]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", version)
+}
+
+// TLSVersion asserts the negotiated TLS protocol version,
+// such as tls.VersionTLS12.
+func TLSVersion(version uint16) Func {
+	return func(res *http.Response, req *http.Request) error {
+		state, err := tlsState(res)
+		if err != nil {
+			return err
+		}
+		if state.Version != version {
+			return fmt.Errorf("TLS version mismatch: %s != %s", TLSVersionName(state.Version), TLSVersionName(version))
+		}
+		return nil
+	}
+}
+
+// TLSCipherSuite asserts the negotiated TLS cipher suite,
+// such as tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func TLSCipherSuite(suite uint16) Func {
+	return func(res *http.Response, req *http.Request) error {
+		state, err := tlsState(res)
+		if err != nil {
+			return err
+		}
+		if state.CipherSuite != suite {
+			return fmt.Errorf("TLS cipher suite mismatch: %s != %s", cipherSuiteName(state.CipherSuite), cipherSuiteName(suite))
+		}
+		return nil
+	}
+}
+
+// TLSPeerSubject asserts the server certificate subject matches the given pattern,
+// such as "CN=example.com,O=Example". Regular expressions are supported.
+func TLSPeerSubject(pattern string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		cert, err := peerCertificate(res)
+		if err != nil {
+			return err
+		}
+		subject := cert.Subject.String()
+		if match, _ := regexp.MatchString(pattern, subject); !match {
+			return fmt.Errorf("TLS peer certificate subject mismatch: '%s' should match '%s'", pattern, subject)
+		}
+		return nil
+	}
+}
+
+// TLSPeerSAN asserts the server certificate contains the given
+// subject alternative name, as DNS name, IP address, email or URI.
+func TLSPeerSAN(name string) Func {
+	return func(res *http.Response, req *http.Request) error {
+		cert, err := peerCertificate(res)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		names = append(names, cert.DNSNames...)
+		names = append(names, cert.EmailAddresses...)
+		for _, ip := range cert.IPAddresses {
+			names = append(names, ip.String())
+		}
+		for _, uri := range cert.URIs {
+			names = append(names, uri.String())
+		}
+
+		for _, san := range names {
+			if san == name {
+				return nil
+			}
+		}
+		return fmt.Errorf("TLS peer certificate SAN not found: %s in %v", name, names)
+	}
+}
+
+// TLSPeerValidFor asserts the server certificate does not expire
+// within the given duration.
+func TLSPeerValidFor(d time.Duration) Func {
+	return func(res *http.Response, req *http.Request) error {
+		cert, err := peerCertificate(res)
+		if err != nil {
+			return err
+		}
+		if deadline := time.Now().Add(d); cert.NotAfter.Before(deadline) {
+			return fmt.Errorf("TLS peer certificate expires before %s: %s",
+				deadline.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+func tlsState(res *http.Response) (*tls.ConnectionState, error) {
+	if res.TLS == nil {
+		return nil, errors.New("TLS connection state not present: the request was not performed over TLS")
+	}
+	return res.TLS, nil
+}
+
+func peerCertificate(res *http.Response) (*x509.Certificate, error) {
+	state, err := tlsState(res)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.New("TLS peer certificate not present")
+	}
+	return state.PeerCertificates[0], nil
+}
+
+func cipherSuiteName(id uint16) string {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.ID == id {
+			return suite.Name
+		}
+	}
+	return fmt.Sprintf("0x%04x", id)
+}
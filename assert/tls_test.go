@@ -0,0 +1,63 @@
+package assert
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+func newTLSResponse() *http.Response {
+	cert := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "foo.com", Organization: []string{"Foo"}},
+		DNSNames:    []string{"foo.com", "*.foo.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotAfter:    time.Now().Add(48 * time.Hour),
+	}
+	return &http.Response{TLS: &tls.ConnectionState{
+		Version:          tls.VersionTLS12,
+		CipherSuite:      tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		PeerCertificates: []*x509.Certificate{cert},
+	}}
+}
+
+func TestTLSVersion(t *testing.T) {
+	res := newTLSResponse()
+	st.Expect(t, TLSVersion(tls.VersionTLS12)(res, nil), nil)
+	st.Expect(t, TLSVersion(tls.VersionTLS13)(res, nil).Error(), "TLS version mismatch: TLS 1.2 != TLS 1.3")
+	st.Expect(t, TLSVersion(tls.VersionTLS12)(&http.Response{}, nil).Error(),
+		"TLS connection state not present: the request was not performed over TLS")
+}
+
+func TestTLSCipherSuite(t *testing.T) {
+	res := newTLSResponse()
+	st.Expect(t, TLSCipherSuite(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256)(res, nil), nil)
+	st.Expect(t, TLSCipherSuite(tls.TLS_AES_128_GCM_SHA256)(res, nil).Error(),
+		"TLS cipher suite mismatch: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 != TLS_AES_128_GCM_SHA256")
+}
+
+func TestTLSPeerSubject(t *testing.T) {
+	res := newTLSResponse()
+	st.Expect(t, TLSPeerSubject("CN=foo.com")(res, nil), nil)
+	st.Expect(t, TLSPeerSubject("^CN=foo.com,O=Foo$")(res, nil), nil)
+	st.Reject(t, TLSPeerSubject("CN=bar.com")(res, nil), nil)
+	st.Reject(t, TLSPeerSubject("CN=bar.com")(&http.Response{TLS: &tls.ConnectionState{}}, nil), nil)
+}
+
+func TestTLSPeerSAN(t *testing.T) {
+	res := newTLSResponse()
+	st.Expect(t, TLSPeerSAN("*.foo.com")(res, nil), nil)
+	st.Expect(t, TLSPeerSAN("127.0.0.1")(res, nil), nil)
+	st.Expect(t, TLSPeerSAN("bar.com")(res, nil).Error(), "TLS peer certificate SAN not found: bar.com in [foo.com *.foo.com 127.0.0.1]")
+}
+
+func TestTLSPeerValidFor(t *testing.T) {
+	res := newTLSResponse()
+	st.Expect(t, TLSPeerValidFor(24*time.Hour)(res, nil), nil)
+	st.Reject(t, TLSPeerValidFor(72*time.Hour)(res, nil), nil)
+}
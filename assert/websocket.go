@@ -0,0 +1,97 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// WebSocket message types, as defined by RFC 6455.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+)
+
+// Message represents a WebSocket data message.
+type Message struct {
+	// Type stores the message type: TextMessage or BinaryMessage.
+	Type int
+	// Data stores the message payload.
+	Data []byte
+}
+
+// MessageMatcher represents the required interface for WebSocket message assertion functions.
+type MessageMatcher func(*Message) error
+
+// MessageText asserts a WebSocket text message using strict comparison.
+func MessageText(text string) MessageMatcher {
+	return func(msg *Message) error {
+		if msg.Type != TextMessage {
+			return fmt.Errorf("Message type mismatch: expected text message, got binary")
+		}
+		if string(msg.Data) != text {
+			return fmt.Errorf("Message mismatch:\n\thave: %#v\n\twant: %#v", string(msg.Data), text)
+		}
+		return nil
+	}
+}
+
+// MessageBinary asserts a WebSocket binary message using strict comparison.
+func MessageBinary(data []byte) MessageMatcher {
+	return func(msg *Message) error {
+		if msg.Type != BinaryMessage {
+			return fmt.Errorf("Message type mismatch: expected binary message, got text")
+		}
+		if !bytes.Equal(msg.Data, data) {
+			return fmt.Errorf("Message mismatch:\n\thave: %#v\n\twant: %#v", msg.Data, data)
+		}
+		return nil
+	}
+}
+
+// MessageMatch asserts a WebSocket message matching the given regular expression.
+func MessageMatch(pattern string) MessageMatcher {
+	return func(msg *Message) error {
+		match, err := regexp.Match(pattern, msg.Data)
+		if err != nil {
+			return err
+		}
+		if !match {
+			return fmt.Errorf("Message mismatch: pattern not found '%s' in %#v", pattern, string(msg.Data))
+		}
+		return nil
+	}
+}
+
+// MessageJSON asserts a WebSocket JSON message contains the given JSON structure.
+// Objects in the message may have additional fields not present in the expected structure.
+func MessageJSON(data interface{}) MessageMatcher {
+	return func(msg *Message) error {
+		value, err := unmarshal(msg.Data)
+		if err != nil {
+			return err
+		}
+
+		var expected []byte
+		switch data := data.(type) {
+		case string:
+			expected = []byte(data)
+		case []byte:
+			expected = data
+		default:
+			if expected, err = json.Marshal(data); err != nil {
+				return err
+			}
+		}
+		match, err := unmarshal(expected)
+		if err != nil {
+			return err
+		}
+
+		if !contains(value, match) {
+			return fmt.Errorf("Message JSON mismatch:\n\thave: %#v\n\twant: %#v", string(msg.Data), string(expected))
+		}
+		return nil
+	}
+}
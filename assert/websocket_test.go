@@ -0,0 +1,40 @@
+package assert
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestMessageText(t *testing.T) {
+	msg := &Message{Type: TextMessage, Data: []byte("hello")}
+	st.Expect(t, MessageText("hello")(msg), nil)
+	st.Expect(t, MessageText("bye")(msg).Error(), "Message mismatch:\n\thave: \"hello\"\n\twant: \"bye\"")
+	st.Expect(t, MessageText("hello")(&Message{Type: BinaryMessage, Data: []byte("hello")}).Error(),
+		"Message type mismatch: expected text message, got binary")
+}
+
+func TestMessageBinary(t *testing.T) {
+	msg := &Message{Type: BinaryMessage, Data: []byte{1, 2}}
+	st.Expect(t, MessageBinary([]byte{1, 2})(msg), nil)
+	st.Reject(t, MessageBinary([]byte{1})(msg), nil)
+	st.Expect(t, MessageBinary([]byte{1, 2})(&Message{Type: TextMessage, Data: []byte{1, 2}}).Error(),
+		"Message type mismatch: expected binary message, got text")
+}
+
+func TestMessageMatch(t *testing.T) {
+	msg := &Message{Type: TextMessage, Data: []byte("hello world")}
+	st.Expect(t, MessageMatch("^hello")(msg), nil)
+	st.Expect(t, MessageMatch("^world")(msg).Error(), "Message mismatch: pattern not found '^world' in \"hello world\"")
+	st.Reject(t, MessageMatch("(")(msg), nil)
+}
+
+func TestMessageJSON(t *testing.T) {
+	msg := &Message{Type: TextMessage, Data: []byte(`{"id":1,"user":{"name":"foo","age":20},"tags":["a","b"]}`)}
+	st.Expect(t, MessageJSON(map[string]interface{}{"id": 1})(msg), nil)
+	st.Expect(t, MessageJSON(`{"user":{"name":"foo"},"tags":["a","b"]}`)(msg), nil)
+	st.Reject(t, MessageJSON(`{"tags":["a"]}`)(msg), nil)
+	st.Reject(t, MessageJSON(`{"user":{"name":"bar"}}`)(msg), nil)
+	st.Reject(t, MessageJSON(`{"missing":null}`)(msg), nil)
+	st.Reject(t, MessageJSON(`{}`)(&Message{Type: TextMessage, Data: []byte("foo")}), nil)
+}
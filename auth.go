@@ -0,0 +1,378 @@
+package baloo
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+	"gopkg.in/h2non/gentleman.v2/plugins/auth"
+)
+
+// API key locations supported by APIKey.
+const (
+	// APIKeyHeader sends the API key as header field.
+	APIKeyHeader = "header"
+	// APIKeyQuery sends the API key as URL query param.
+	APIKeyQuery = "query"
+)
+
+// BasicAuth defines the HTTP Basic authentication credentials used by client requests.
+func (c *Client) BasicAuth(username, password string) *Client {
+	return c.Use(auth.Basic(username, password))
+}
+
+// BearerToken defines the Bearer token used by client requests as Authorization header.
+func (c *Client) BearerToken(token string) *Client {
+	return c.Use(auth.Bearer(token))
+}
+
+// APIKey defines the API key sent by client requests in the given location,
+// which can be APIKeyHeader or APIKeyQuery, using the given field name.
+func (c *Client) APIKey(in, name, value string) *Client {
+	return c.Use(apiKey(in, name, value))
+}
+
+// Digest defines the HTTP Digest authentication credentials used by client requests.
+// Requests are retried with the proper credentials once the server replies with
+// a 401 Digest challenge, which is reused by the next client requests.
+func (c *Client) Digest(username, password string) *Client {
+	return c.Use(newDigestAuth(username, password))
+}
+
+// BasicAuth defines the HTTP Basic authentication credentials.
+func (r *Request) BasicAuth(username, password string) *Request {
+	return r.Use(auth.Basic(username, password))
+}
+
+// BearerToken defines the Bearer token sent as Authorization header.
+func (r *Request) BearerToken(token string) *Request {
+	return r.Use(auth.Bearer(token))
+}
+
+// APIKey defines the API key sent in the given location,
+// which can be APIKeyHeader or APIKeyQuery, using the given field name.
+func (r *Request) APIKey(in, name, value string) *Request {
+	return r.Use(apiKey(in, name, value))
+}
+
+// Digest defines the HTTP Digest authentication credentials.
+// The request is retried with the proper credentials once the
+// server replies with a 401 Digest challenge.
+func (r *Request) Digest(username, password string) *Request {
+	return r.Use(newDigestAuth(username, password))
+}
+
+func apiKey(in, name, value string) plugin.Plugin {
+	return plugin.NewRequestPlugin(func(ctx *context.Context, h context.Handler) {
+		switch in {
+		case APIKeyHeader:
+			ctx.Request.Header.Set(name, value)
+		case APIKeyQuery:
+			query := ctx.Request.URL.Query()
+			query.Set(name, value)
+			ctx.Request.URL.RawQuery = query.Encode()
+		default:
+			h.Error(ctx, fmt.Errorf("invalid API key location: %s", in))
+			return
+		}
+		h.Next(ctx)
+	})
+}
+
+// digestAuth implements the HTTP Digest access authentication (RFC 7616)
+// as plugin, retrying the request on 401 Digest challenges.
+type digestAuth struct {
+	*plugin.Layer
+
+	username string
+	password string
+
+	mutex     sync.Mutex
+	challenge map[string]string
+	count     int
+}
+
+func newDigestAuth(username, password string) *digestAuth {
+	d := &digestAuth{Layer: plugin.New(), username: username, password: password}
+	d.SetHandlers(plugin.Handlers{
+		"before dial": d.beforeDial,
+		"response":    d.response,
+	})
+	return d
+}
+
+// beforeDial authorizes the request using the last received challenge, if any.
+// The request body is buffered to be sent again if the request is retried.
+func (d *digestAuth) beforeDial(ctx *context.Context, h context.Handler) {
+	if err := bufferBody(ctx.Request); err != nil {
+		h.Error(ctx, err)
+		return
+	}
+
+	d.mutex.Lock()
+	challenge := d.challenge
+	d.mutex.Unlock()
+
+	if challenge != nil && !retrying(ctx) {
+		if err := d.authorize(ctx.Request, challenge); err != nil {
+			h.Error(ctx, err)
+			return
+		}
+	}
+	h.Next(ctx)
+}
+
+// response retries the request once the server replies with a Digest challenge.
+func (d *digestAuth) response(ctx *context.Context, h context.Handler) {
+	res := ctx.Response
+	if res.StatusCode != http.StatusUnauthorized {
+		h.Next(ctx)
+		return
+	}
+
+	challenge := parseDigestChallenge(res.Header.Get("WWW-Authenticate"))
+	if challenge == nil {
+		h.Next(ctx)
+		return
+	}
+
+	// Do not retry if the credentials were rejected for a fresh nonce
+	if ctx.Request.Header.Get("Authorization") != "" && !strings.EqualFold(challenge["stale"], "true") {
+		h.Next(ctx)
+		return
+	}
+
+	d.mutex.Lock()
+	d.challenge = challenge
+	d.count = 0
+	d.mutex.Unlock()
+
+	err := retryRequest(ctx, func(req *http.Request) error {
+		return d.authorize(req, challenge)
+	})
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	h.Next(ctx)
+}
+
+// authorize sets the Authorization header based on the given challenge.
+func (d *digestAuth) authorize(req *http.Request, challenge map[string]string) error {
+	algorithm := challenge["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return fmt.Errorf("unsupported digest algorithm: %s", algorithm)
+	}
+	digest := func(values ...string) string {
+		h := newHash()
+		io.WriteString(h, strings.Join(values, ":"))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	qop := ""
+	if value, ok := challenge["qop"]; ok {
+		for _, option := range strings.Split(value, ",") {
+			if strings.TrimSpace(option) == "auth" {
+				qop = "auth"
+			}
+		}
+		if qop == "" {
+			return fmt.Errorf("unsupported digest qop: %s", value)
+		}
+	}
+
+	d.mutex.Lock()
+	d.count++
+	nc := fmt.Sprintf("%08x", d.count)
+	d.mutex.Unlock()
+
+	cnonce, err := newCnonce()
+	if err != nil {
+		return err
+	}
+
+	realm, nonce, uri := challenge["realm"], challenge["nonce"], req.URL.RequestURI()
+	ha1 := digest(d.username, realm, d.password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = digest(ha1, nonce, cnonce)
+	}
+	ha2 := digest(req.Method, uri)
+
+	fields := []string{
+		fmt.Sprintf(`username="%s"`, quoteEscaper.Replace(d.username)),
+		fmt.Sprintf(`realm="%s"`, quoteEscaper.Replace(realm)),
+		fmt.Sprintf(`nonce="%s"`, quoteEscaper.Replace(nonce)),
+		fmt.Sprintf(`uri="%s"`, quoteEscaper.Replace(uri)),
+		fmt.Sprintf(`algorithm=%s`, algorithm),
+	}
+	if qop != "" {
+		fields = append(fields,
+			fmt.Sprintf(`response="%s"`, digest(ha1, nonce, nc, cnonce, qop, ha2)),
+			fmt.Sprintf(`qop=%s`, qop),
+			fmt.Sprintf(`nc=%s`, nc),
+			fmt.Sprintf(`cnonce="%s"`, cnonce),
+		)
+	} else {
+		fields = append(fields, fmt.Sprintf(`response="%s"`, digest(ha1, nonce, ha2)))
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		fields = append(fields, fmt.Sprintf(`opaque="%s"`, quoteEscaper.Replace(opaque)))
+	}
+
+	req.Header.Set("Authorization", "Digest "+strings.Join(fields, ", "))
+	return nil
+}
+
+// quoteEscaper escapes the Digest header quoted string values.
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func newCnonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// parseDigestChallenge parses the params of a Digest WWW-Authenticate challenge.
+// Returns nil if the challenge is not a Digest challenge.
+func parseDigestChallenge(header string) map[string]string {
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Digest ") {
+		return nil
+	}
+
+	params := map[string]string{}
+	s := header[7:]
+	for {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			// Quoted string value, which can contain commas and escaped quotes
+			buf := &strings.Builder{}
+			i := 1
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				buf.WriteByte(s[i])
+			}
+			value = buf.String()
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// retryKey flags the request context while a retried request runs the
+// before dial middleware, so authentication plugins keep its authorization.
+const retryKey = "$baloo.retry"
+
+// retrying returns true if the context request is being retried.
+func retrying(ctx *context.Context) bool {
+	retry, _ := ctx.Get(retryKey).(bool)
+	return retry
+}
+
+// retryRequest sends again the context request authorized by the given function,
+// replacing the context request and response with the retried ones.
+// The before and after dial middleware phases run again for the retried request,
+// so plugins such as signing, recording or TLS settings apply to it as well.
+func retryRequest(ctx *context.Context, authorize func(*http.Request) error) error {
+	// Shallow copy the request with its own header fields
+	req := ctx.Request.WithContext(ctx.Request.Context())
+	req.Header = http.Header{}
+	for key, values := range ctx.Request.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if ctx.Request.GetBody != nil {
+		body, err := ctx.Request.GetBody()
+		if err != nil {
+			return err
+		}
+		req.Body = body
+	} else if ctx.Request.Body != nil && ctx.Request.Body != http.NoBody && ctx.Request.ContentLength != 0 {
+		return errors.New("cannot retry request: the request body cannot be read again")
+	}
+	if err := authorize(req); err != nil {
+		return err
+	}
+
+	stack, _ := ctx.Get(middlewareKey).([]plugin.Plugin)
+	prev := ctx.Response
+	ctx.Request = req
+
+	ctx.Set(retryKey, true)
+	ctx = runPlugins("before dial", stack, ctx)
+	ctx.Delete(retryKey)
+	if ctx.Error != nil {
+		return ctx.Error
+	}
+
+	res, err := ctx.Client.Do(ctx.Request)
+	if err != nil {
+		return err
+	}
+
+	io.Copy(ioutil.Discard, prev.Body)
+	prev.Body.Close()
+
+	ctx.Response = res
+	ctx = runPlugins("after dial", stack, ctx)
+	return ctx.Error
+}
+
+// runPlugins runs the given middleware phase handlers of the plugins stack,
+// stopping once a plugin stops the call chain or reports an error.
+// The gentleman middleware cannot be run from within its own handlers,
+// as it is locked until the phase call chain ends.
+func runPlugins(phase string, stack []plugin.Plugin, ctx *context.Context) *context.Context {
+	for _, p := range stack {
+		called := false
+		p.Exec(phase, ctx, context.NewHandler(func(next *context.Context) {
+			ctx, called = next, true
+		}))
+		if !called || ctx.Error != nil || ctx.Stopped {
+			break
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,206 @@
+package baloo
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+func md5Hex(values ...string) string {
+	sum := md5.Sum([]byte(strings.Join(values, ":")))
+	return hex.EncodeToString(sum[:])
+}
+
+// createDigestServer creates a server requiring Digest authentication for user foo:bar,
+// which echoes the received request body.
+func createDigestServer(requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		challenge := `Digest realm="test", qop="auth,auth-int", nonce="abc, 123", opaque="xyz"`
+
+		params := parseDigestChallenge(r.Header.Get("Authorization"))
+		if params == nil {
+			w.Header().Set("WWW-Authenticate", challenge)
+			w.WriteHeader(401)
+			return
+		}
+
+		ha1 := md5Hex("foo", "test", "bar")
+		ha2 := md5Hex(r.Method, r.URL.RequestURI())
+		expected := md5Hex(ha1, params["nonce"], params["nc"], params["cnonce"], params["qop"], ha2)
+		if params["response"] != expected || params["opaque"] != "xyz" || params["uri"] != r.URL.RequestURI() {
+			w.Header().Set("WWW-Authenticate", challenge)
+			w.WriteHeader(401)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "nc=%s body=%s", params["nc"], body)
+	}))
+}
+
+func createAuthorizationServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+}
+
+func TestClientBasicAuth(t *testing.T) {
+	ts := createAuthorizationServer()
+	defer ts.Close()
+
+	New(ts.URL).BasicAuth("foo", "bar").Get("/").
+		Expect(t).
+		BodyEquals("Basic Zm9vOmJhcg==").
+		Done()
+}
+
+func TestRequestBearerToken(t *testing.T) {
+	ts := createAuthorizationServer()
+	defer ts.Close()
+
+	New(ts.URL).BearerToken("foo").Get("/").BearerToken("secret").
+		Expect(t).
+		BodyEquals("Bearer secret").
+		Done()
+}
+
+func TestClientAPIKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "header=%s query=%s", r.Header.Get("X-API-Key"), r.URL.RawQuery)
+	}))
+	defer ts.Close()
+
+	New(ts.URL).APIKey(APIKeyHeader, "X-API-Key", "secret").Get("/").
+		Expect(t).
+		BodyEquals("header=secret query=").
+		Done()
+
+	New(ts.URL).Get("/").AddQuery("foo", "bar").APIKey(APIKeyQuery, "api_key", "secret").
+		Expect(t).
+		BodyEquals("header= query=api_key=secret&foo=bar").
+		Done()
+
+	_, err := New(ts.URL).Get("/").APIKey("cookie", "key", "secret").Send()
+	st.Expect(t, err.Error(), "invalid API key location: cookie")
+}
+
+func TestClientDigest(t *testing.T) {
+	var requests int32
+	ts := createDigestServer(&requests)
+	defer ts.Close()
+
+	cli := New(ts.URL).Digest("foo", "bar")
+	cli.Post("/foo").AddQuery("a", "b").BodyString("hello").
+		Expect(t).
+		Status(200).
+		BodyEquals("nc=00000001 body=hello").
+		Done()
+	st.Expect(t, atomic.LoadInt32(&requests), int32(2))
+
+	// The received challenge is reused by the next requests
+	cli.Get("/bar").
+		Expect(t).
+		Status(200).
+		BodyEquals("nc=00000002 body=").
+		Done()
+	st.Expect(t, atomic.LoadInt32(&requests), int32(3))
+}
+
+func TestDigestStreamedBody(t *testing.T) {
+	var requests int32
+	ts := createDigestServer(&requests)
+	defer ts.Close()
+
+	// Plain gentleman request, whose streamed body does not define GetBody
+	cli := gentleman.New()
+	cli.URL(ts.URL)
+	cli.Use(newDigestAuth("foo", "bar"))
+	req := cli.Request()
+	req.Method("POST")
+	req.Body(io.MultiReader(strings.NewReader("hello "), strings.NewReader("world")))
+
+	res, err := req.Send()
+	st.Expect(t, err, nil)
+	st.Expect(t, res.StatusCode, 200)
+	st.Expect(t, res.String(), "nc=00000001 body=hello world")
+	st.Expect(t, atomic.LoadInt32(&requests), int32(2))
+}
+
+func TestDigestRetryMiddleware(t *testing.T) {
+	var requests, signed int32
+	ts := createDigestServer(&requests)
+	defer ts.Close()
+
+	cli := New(ts.URL).Digest("foo", "bar")
+	cli.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		atomic.AddInt32(&signed, 1)
+		ctx.Request.Header.Set("X-Signature", ctx.Request.Header.Get("Authorization"))
+		h.Next(ctx)
+	})
+	cli.Get("/").
+		Expect(t).
+		Status(200).
+		AssertFunc(func(res *http.Response, req *http.Request) error {
+			if req.Header.Get("X-Signature") != req.Header.Get("Authorization") {
+				return errors.New("retried request was not signed")
+			}
+			return nil
+		}).
+		Done()
+	st.Expect(t, atomic.LoadInt32(&requests), int32(2))
+	st.Expect(t, atomic.LoadInt32(&signed), int32(2))
+}
+
+func TestDigestEscapedValues(t *testing.T) {
+	d := newDigestAuth(`fo"o\`, "bar")
+	req, _ := http.NewRequest("GET", `http://foo.com/"a"`, nil)
+	err := d.authorize(req, map[string]string{"realm": `a "b"`, "nonce": "123"})
+	st.Expect(t, err, nil)
+
+	params := parseDigestChallenge(req.Header.Get("Authorization"))
+	st.Expect(t, params["username"], `fo"o\`)
+	st.Expect(t, params["realm"], `a "b"`)
+	st.Expect(t, params["uri"], req.URL.RequestURI())
+}
+
+func TestRetryRequestWithoutGetBody(t *testing.T) {
+	ctx := context.New()
+	ctx.Request.Body = ioutil.NopCloser(strings.NewReader("hello"))
+	ctx.Request.ContentLength = -1
+	err := retryRequest(ctx, func(*http.Request) error { return nil })
+	st.Expect(t, err.Error(), "cannot retry request: the request body cannot be read again")
+}
+
+func TestRequestDigestInvalidCredentials(t *testing.T) {
+	var requests int32
+	ts := createDigestServer(&requests)
+	defer ts.Close()
+
+	New(ts.URL).Get("/").Digest("foo", "invalid").
+		Expect(t).
+		Status(401).
+		Done()
+	st.Expect(t, atomic.LoadInt32(&requests), int32(2))
+}
+
+func TestParseDigestChallenge(t *testing.T) {
+	st.Expect(t, parseDigestChallenge(`Basic realm="foo"`), map[string]string(nil))
+	st.Expect(t, parseDigestChallenge(`Digest realm="a \"b\", c", nonce=123, stale=TRUE`), map[string]string{
+		"realm": `a "b", c`,
+		"nonce": "123",
+		"stale": "TRUE",
+	})
+}
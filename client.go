@@ -3,6 +3,7 @@ package baloo
 import (
 	"net/http"
 
+	"gopkg.in/h2non/baloo.v3/assert"
 	"gopkg.in/h2non/gentleman.v2"
 	"gopkg.in/h2non/gentleman.v2/context"
 	"gopkg.in/h2non/gentleman.v2/plugin"
@@ -18,6 +19,15 @@ type Client struct {
 	Parent *Client
 	// Client entity has it's own Context that will be inherited by requests or child clients.
 	Client *gentleman.Client
+	// Assertions stores the client assertion functions by alias name,
+	// inherited from the parent client or global registry.
+	Assertions *Registry
+
+	// debug stores the HTTP exchange debugging options.
+	debug debugOptions
+
+	// oauth2 stores the OAuth2 plugin, if configured.
+	oauth2 *oauth2Auth
 }
 
 // New creates a new high level client entity
@@ -25,7 +35,7 @@ type Client struct {
 func New(url string) *Client {
 	cli := gentleman.New()
 	cli.URL(url)
-	return &Client{Client: cli}
+	return &Client{Client: cli, Assertions: NewRegistry(DefaultRegistry)}
 }
 
 // Request creates a new Request based on the current Client
@@ -194,10 +204,57 @@ func (c *Client) UseHandler(phase string, fn context.HandlerFunc) *Client {
 	return c
 }
 
+// AddAssertFunc adds a new assertion function by alias name
+// available to requests created by this client and its child clients.
+func (c *Client) AddAssertFunc(name string, fn assert.Func) *Client {
+	c.Assertions.Add(name, fn)
+	return c
+}
+
+// AddAssertFactory adds a new parameterized assertion function by alias name
+// available to requests created by this client and its child clients.
+func (c *Client) AddAssertFactory(name string, factory AssertFactory) *Client {
+	c.Assertions.AddFactory(name, factory)
+	return c
+}
+
+// DumpOnFailure enables or disables printing a full dump of the
+// HTTP request and response when an expectation fails.
+func (c *Client) DumpOnFailure(enable bool) *Client {
+	c.debug.dump = enable
+	return c
+}
+
+// Verbose enables or disables logging every HTTP exchange
+// performed by the client requests via the test logger.
+func (c *Client) Verbose(enable bool) *Client {
+	c.debug.verbose = enable
+	return c
+}
+
+// RedactHeaders defines additional header fields whose values
+// will be redacted in HTTP request/response dumps.
+func (c *Client) RedactHeaders(names ...string) *Client {
+	c.debug.redact = append(c.debug.redact, names...)
+	return c
+}
+
+// debugOptions returns the client debugging options
+// merged with the parent client and global options.
+func (c *Client) debugOptions() debugOptions {
+	if c.Parent != nil {
+		return c.debug.merge(c.Parent.debugOptions())
+	}
+	return c.debug.merge(envDebugOptions())
+}
+
 // UseParent uses another Client as parent
 // inheriting its middleware stack and configuration.
 func (c *Client) UseParent(parent *Client) *Client {
 	c.Parent = parent
 	c.Client.UseParent(parent.Client)
+	if c.Assertions != nil && parent.Assertions != nil {
+		c.Assertions.SetParent(parent.Assertions)
+	}
 	return c
 }
@@ -0,0 +1,89 @@
+// Command baloo provides command line tools for baloo based tests.
+//
+// Usage:
+//
+//	baloo gen [-pkg name] [-env environment.json] [-o output_test.go] <file.har|collection.json>
+//
+// The gen command generates Go test files based on a HAR file
+// or Postman v2.1 collection, using the recorded status, content type
+// and JSON body as starting expectations.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"gopkg.in/h2non/baloo.v3/gen"
+)
+
+const usage = `Usage: baloo <command> [arguments]
+
+Commands:
+  gen    generate Go tests from a HAR file or Postman v2.1 collection
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "gen":
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "baloo: %s\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func generate(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ExitOnError)
+	pkg := flags.String("pkg", "api", "Go package name of the generated file")
+	envPath := flags.String("env", "", "Postman environment file used to resolve variables")
+	output := flags.String("o", "", "output file path (defaults to stdout)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: baloo gen [flags] <file.har|collection.json>\n\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var env []byte
+	if *envPath != "" {
+		if env, err = ioutil.ReadFile(*envPath); err != nil {
+			return err
+		}
+	}
+
+	suite, err := gen.Parse(data, env)
+	if err != nil {
+		return err
+	}
+	suite.Package = *pkg
+
+	code, err := gen.Generate(suite)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return ioutil.WriteFile(*output, code, 0644)
+}
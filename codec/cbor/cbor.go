@@ -0,0 +1,20 @@
+// Package cbor implements the Concise Binary Object Representation (RFC 8949) codec,
+// registered for the application/cbor content type.
+// Import it for its side effects to enable CBOR bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/codec/cbor"
+package cbor
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+// Codec implements the CBOR codec.
+var Codec codec.Codec = &codec.Funcs{MarshalFunc: cbor.Marshal, UnmarshalFunc: cbor.Unmarshal}
+
+func init() {
+	for _, contentType := range []string{"application/cbor"} {
+		codec.Register(contentType, Codec)
+	}
+}
@@ -0,0 +1,28 @@
+package cbor
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+func TestRegister(t *testing.T) {
+	for _, contentType := range []string{"application/cbor", "application/vnd.api+cbor"} {
+		found, err := codec.Lookup(contentType)
+		st.Expect(t, err, nil)
+		st.Expect(t, found, Codec)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := map[string]interface{}{"foo": "bar", "list": []interface{}{"baz"}}
+	buf, err := codec.Marshal("application/cbor", data)
+	st.Expect(t, err, nil)
+
+	var value interface{}
+	st.Expect(t, codec.Unmarshal("application/cbor", buf, &value), nil)
+	normalized, err := codec.Normalize(value)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(normalized), `{"foo":"bar","list":["baz"]}`)
+}
@@ -0,0 +1,162 @@
+// Package codec implements a registry of body serialization formats keyed by content type,
+// used to encode request bodies and decode response bodies beyond JSON.
+//
+// Only JSON is registered by default. Protocol Buffers, MessagePack, CBOR and YAML
+// are implemented by subpackages registering themselves once imported:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/codec/yaml"
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Codec represents a body serialization format.
+type Codec interface {
+	// Marshal serializes the given value.
+	Marshal(v interface{}) ([]byte, error)
+	// Unmarshal deserializes the given data into the value pointed by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var (
+	codecsMutex = &sync.RWMutex{}
+
+	// codecs stores the registered codecs by media type.
+	codecs = map[string]Codec{
+		"application/json": JSON,
+	}
+)
+
+// Normalizer is implemented by codecs whose decoded values have
+// a canonical JSON representation, such as Protocol Buffers messages.
+type Normalizer interface {
+	// Normalize returns the JSON representation of the given value,
+	// or false if the value is not handled by the codec.
+	Normalize(v interface{}) ([]byte, bool, error)
+}
+
+// Register registers a new codec by content type.
+// Content type parameters are ignored and existing codecs
+// for the same media type are replaced.
+func Register(contentType string, codec Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+	codecs[mediaType(contentType)] = codec
+}
+
+// Types returns the media types with a registered codec, sorted alphabetically.
+func Types() []string {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	types := make([]string, 0, len(codecs))
+	for name := range codecs {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Lookup returns the codec registered for the given content type.
+// Structured syntax suffixes, such as "application/problem+json",
+// fall back to the codec of the suffix format.
+func Lookup(contentType string) (Codec, error) {
+	name := mediaType(contentType)
+
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	if codec, ok := codecs[name]; ok {
+		return codec, nil
+	}
+	if i := strings.LastIndexByte(name, '+'); i >= 0 {
+		if codec, ok := codecs["application/"+name[i+1:]]; ok {
+			return codec, nil
+		}
+	}
+	return nil, fmt.Errorf("codec: unsupported content type '%s'", contentType)
+}
+
+// Marshal serializes the given value with the codec of the given content type.
+func Marshal(contentType string, v interface{}) ([]byte, error) {
+	codec, err := Lookup(contentType)
+	if err != nil {
+		return nil, err
+	}
+	return codec.Marshal(v)
+}
+
+// Unmarshal deserializes the given data with the codec of the given content type.
+func Unmarshal(contentType string, data []byte, v interface{}) error {
+	codec, err := Lookup(contentType)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, v)
+}
+
+// Normalize converts the given decoded value into its JSON representation,
+// so values decoded by any codec can be compared with the same semantics.
+// Values handled by a registered Normalizer, such as Protocol Buffers messages,
+// use its representation, and map keys of any type are converted to strings.
+func Normalize(v interface{}) ([]byte, error) {
+	for _, normalizer := range normalizers() {
+		if buf, ok, err := normalizer.Normalize(v); ok {
+			return buf, err
+		}
+	}
+	return json.Marshal(normalize(v))
+}
+
+// normalizers returns the registered codecs implementing the Normalizer interface.
+func normalizers() []Normalizer {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	var list []Normalizer
+	for _, codec := range codecs {
+		if normalizer, ok := codec.(Normalizer); ok {
+			list = append(list, normalizer)
+		}
+	}
+	return list
+}
+
+// normalize recursively converts maps with non-string keys into JSON objects.
+func normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			object[fmt.Sprint(key)] = normalize(field)
+		}
+		return object
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			object[key] = normalize(field)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = normalize(item)
+		}
+		return list
+	}
+	return v
+}
+
+// mediaType returns the lower cased media type of the given content type, without parameters.
+func mediaType(contentType string) string {
+	if name, _, err := mime.ParseMediaType(contentType); err == nil {
+		return name
+	}
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,94 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestLookup(t *testing.T) {
+	for _, contentType := range []string{"application/json", "Application/JSON", "application/problem+json; charset=utf-8"} {
+		codec, err := Lookup(contentType)
+		st.Expect(t, err, nil)
+		st.Expect(t, codec, JSON)
+	}
+
+	_, err := Lookup("application/octet-stream")
+	st.Expect(t, err.Error(), "codec: unsupported content type 'application/octet-stream'")
+}
+
+func TestRegister(t *testing.T) {
+	upper := Funcs{
+		MarshalFunc: func(v interface{}) ([]byte, error) {
+			return []byte(v.(string) + "!"), nil
+		},
+		UnmarshalFunc: func(data []byte, v interface{}) error {
+			*v.(*interface{}) = string(data)
+			return nil
+		},
+	}
+	Register("Text/X-Shout; charset=utf-8", upper)
+	defer func() {
+		codecsMutex.Lock()
+		delete(codecs, "text/x-shout")
+		codecsMutex.Unlock()
+	}()
+
+	buf, err := Marshal("text/x-shout", "foo")
+	st.Expect(t, err, nil)
+	st.Expect(t, string(buf), "foo!")
+
+	var value interface{}
+	st.Expect(t, Unmarshal("text/x-shout", buf, &value), nil)
+	st.Expect(t, value, "foo!")
+
+	st.Expect(t, len(Types()) > 0, true)
+	st.Expect(t, Types()[0] <= Types()[1], true)
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := map[string]interface{}{"foo": "bar", "list": []interface{}{"baz"}}
+	buf, err := Marshal("application/json", data)
+	st.Expect(t, err, nil)
+
+	var value interface{}
+	st.Expect(t, Unmarshal("application/json", buf, &value), nil)
+	normalized, err := Normalize(value)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(normalized), `{"foo":"bar","list":["baz"]}`)
+}
+
+func TestNormalize(t *testing.T) {
+	value := map[interface{}]interface{}{1: map[interface{}]interface{}{true: "foo"}}
+	buf, err := Normalize(value)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(buf), `{"1":{"true":"foo"}}`)
+}
+
+type quoted string
+
+type quotedCodec struct {
+	Funcs
+}
+
+func (quotedCodec) Normalize(v interface{}) ([]byte, bool, error) {
+	value, ok := v.(quoted)
+	return []byte(`"'` + string(value) + `'"`), ok, nil
+}
+
+func TestNormalizer(t *testing.T) {
+	Register("text/x-quoted", quotedCodec{})
+	defer func() {
+		codecsMutex.Lock()
+		delete(codecs, "text/x-quoted")
+		codecsMutex.Unlock()
+	}()
+
+	buf, err := Normalize(quoted("foo"))
+	st.Expect(t, err, nil)
+	st.Expect(t, string(buf), `"'foo'"`)
+
+	buf, err = Normalize("foo")
+	st.Expect(t, err, nil)
+	st.Expect(t, string(buf), `"foo"`)
+}
@@ -0,0 +1,22 @@
+package codec
+
+import "encoding/json"
+
+// Funcs implements the Codec interface based on a pair of functions.
+type Funcs struct {
+	MarshalFunc   func(v interface{}) ([]byte, error)
+	UnmarshalFunc func(data []byte, v interface{}) error
+}
+
+// Marshal serializes the given value using MarshalFunc.
+func (f Funcs) Marshal(v interface{}) ([]byte, error) {
+	return f.MarshalFunc(v)
+}
+
+// Unmarshal deserializes the given data using UnmarshalFunc.
+func (f Funcs) Unmarshal(data []byte, v interface{}) error {
+	return f.UnmarshalFunc(data, v)
+}
+
+// JSON implements the JSON codec.
+var JSON Codec = &Funcs{json.Marshal, json.Unmarshal}
@@ -0,0 +1,20 @@
+// Package msgpack implements the MessagePack codec, registered for the
+// application/msgpack, application/x-msgpack and application/vnd.msgpack content types.
+// Import it for its side effects to enable MessagePack bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/codec/msgpack"
+package msgpack
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+// Codec implements the MessagePack codec.
+var Codec codec.Codec = &codec.Funcs{MarshalFunc: msgpack.Marshal, UnmarshalFunc: msgpack.Unmarshal}
+
+func init() {
+	for _, contentType := range []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"} {
+		codec.Register(contentType, Codec)
+	}
+}
@@ -0,0 +1,28 @@
+package msgpack
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+func TestRegister(t *testing.T) {
+	for _, contentType := range []string{"application/x-msgpack", "application/vnd.msgpack; charset=binary"} {
+		found, err := codec.Lookup(contentType)
+		st.Expect(t, err, nil)
+		st.Expect(t, found, Codec)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := map[string]interface{}{"foo": "bar", "list": []interface{}{"baz"}}
+	buf, err := codec.Marshal("application/x-msgpack", data)
+	st.Expect(t, err, nil)
+
+	var value interface{}
+	st.Expect(t, codec.Unmarshal("application/x-msgpack", buf, &value), nil)
+	normalized, err := codec.Normalize(value)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(normalized), `{"foo":"bar","list":["baz"]}`)
+}
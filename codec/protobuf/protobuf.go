@@ -0,0 +1,54 @@
+// Package protobuf implements the Protocol Buffers binary codec, registered for the
+// application/x-protobuf, application/protobuf and application/vnd.google.protobuf content types.
+// Decoded messages are normalized using the canonical JSON mapping.
+// Import it for its side effects to enable Protocol Buffers bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/codec/protobuf"
+package protobuf
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+// Codec implements the Protocol Buffers binary codec.
+// Values must implement proto.Message.
+var Codec codec.Codec = protobufCodec{}
+
+func init() {
+	for _, contentType := range []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"} {
+		codec.Register(contentType, Codec)
+	}
+}
+
+// protobufCodec implements the codec.Codec and codec.Normalizer interfaces.
+type protobufCodec struct{}
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("codec: protobuf requires a proto.Message, got %T", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("codec: protobuf requires a proto.Message, got %T", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// Normalize represents Protocol Buffers messages using the canonical JSON mapping.
+func (protobufCodec) Normalize(v interface{}) ([]byte, bool, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, false, nil
+	}
+	buf, err := protojson.Marshal(msg)
+	return buf, true, err
+}
@@ -0,0 +1,33 @@
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/h2non/baloo.v3/codec"
+)
+
+func TestRegister(t *testing.T) {
+	for _, contentType := range []string{"application/x-protobuf; proto=foo.Bar", "application/protobuf"} {
+		found, err := codec.Lookup(contentType)
+		st.Expect(t, err, nil)
+		st.Expect(t, found, Codec)
+	}
+}
+
+func TestProtobuf(t *testing.T) {
+	buf, err := Codec.Marshal(wrapperspb.String("foo"))
+	st.Expect(t, err, nil)
+
+	message := &wrapperspb.StringValue{}
+	st.Expect(t, Codec.Unmarshal(buf, message), nil)
+	st.Expect(t, message.GetValue(), "foo")
+
+	normalized, err := codec.Normalize(message)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(normalized), `"foo"`)
+
+	_, err = Codec.Marshal("foo")
+	st.Expect(t, err.Error(), "codec: protobuf requires a proto.Message, got string")
+}
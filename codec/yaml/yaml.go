@@ -0,0 +1,20 @@
+// Package yaml implements the YAML codec, registered for the
+// application/yaml, application/x-yaml, text/yaml and text/x-yaml content types.
+// Import it for its side effects to enable YAML bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/codec/yaml"
+package yaml
+
+import (
+	"gopkg.in/h2non/baloo.v3/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Codec implements the YAML codec.
+var Codec codec.Codec = &codec.Funcs{MarshalFunc: yaml.Marshal, UnmarshalFunc: yaml.Unmarshal}
+
+func init() {
+	for _, contentType := range []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"} {
+		codec.Register(contentType, Codec)
+	}
+}
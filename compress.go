@@ -0,0 +1,319 @@
+package baloo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/baloo.v3/har"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// Decoder creates a reader decoding the given content encoded stream.
+type Decoder func(io.Reader) (io.ReadCloser, error)
+
+// Encoder creates a writer encoding the data written to the given stream.
+type Encoder func(io.Writer) (io.WriteCloser, error)
+
+// encoding stores the encoder and decoder of a content encoding.
+type encoding struct {
+	encoder Encoder
+	decoder Decoder
+}
+
+var (
+	encodingsMutex = &sync.RWMutex{}
+
+	// encodings stores the supported content encodings by name.
+	encodings = map[string]encoding{
+		"gzip": {
+			encoder: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			decoder: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		"deflate": {
+			encoder: func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil },
+			decoder: decodeDeflate,
+		},
+	}
+)
+
+// RegisterEncoding registers a new content encoding by name,
+// used to compress request bodies and decompress response bodies.
+// Existing encodings with the same name are replaced.
+// The br and zstd encodings are registered by importing the
+// encoding/brotli and encoding/zstd packages respectively.
+func RegisterEncoding(name string, encoder Encoder, decoder Decoder) {
+	encodingsMutex.Lock()
+	defer encodingsMutex.Unlock()
+	encodings[strings.ToLower(name)] = encoding{encoder: encoder, decoder: decoder}
+}
+
+// Encodings returns the names of the supported content encodings, sorted alphabetically.
+func Encodings() []string {
+	encodingsMutex.RLock()
+	defer encodingsMutex.RUnlock()
+	return httputil.SortedKeys(encodings)
+}
+
+func getEncoding(name string) (encoding, error) {
+	encodingsMutex.RLock()
+	defer encodingsMutex.RUnlock()
+	enc, ok := encodings[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return enc, fmt.Errorf("unsupported content encoding: %s", name)
+	}
+	return enc, nil
+}
+
+// decodeDeflate decodes zlib wrapped deflate streams,
+// falling back to raw deflate streams sent by some servers.
+func decodeDeflate(r io.Reader) (io.ReadCloser, error) {
+	buf := bufio.NewReader(r)
+	header, err := buf.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// Zlib header: compression method 8 and a valid checksum
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buf)
+	}
+	return flate.NewReader(buf), nil
+}
+
+// Decompress enables the transparent decompression of response bodies
+// encoded with any of the registered content encodings, such as gzip and deflate.
+// The Accept-Encoding header is defined with the supported encodings, if not present.
+func (c *Client) Decompress() *Client {
+	return c.Use(decompressor())
+}
+
+// Decompress enables the transparent decompression of the response body
+// encoded with any of the registered content encodings, such as gzip and deflate.
+// The Accept-Encoding header is defined with the supported encodings, if not present.
+func (r *Request) Decompress() *Request {
+	return r.Use(decompressor())
+}
+
+// Compress compresses the request body with the given content encoding,
+// such as gzip or deflate, and defines the Content-Encoding header.
+func (r *Request) Compress(encoding string) *Request {
+	r.Request.UseHandler("before dial", func(ctx *context.Context, h context.Handler) {
+		if err := compressBody(ctx.Request, encoding); err != nil {
+			h.Error(ctx, err)
+			return
+		}
+		h.Next(ctx)
+	})
+	return r
+}
+
+// ContentEncoding asserts the response Content-Encoding header,
+// such as gzip, br or zstd. Use "identity" to assert uncompressed responses.
+func (e *Expect) ContentEncoding(encoding string) *Expect {
+	e.AssertFunc(assert.ContentEncoding(encoding))
+	return e
+}
+
+// CompressedLength asserts the length of the compressed response body.
+// Requires Decompress() to be enabled.
+func (e *Expect) CompressedLength(length int) *Expect {
+	e.AssertFunc(assert.CompressedLength(length))
+	return e
+}
+
+// Compressed asserts the compressed response body is smaller
+// than the decompressed one. Requires Decompress() to be enabled.
+func (e *Expect) Compressed() *Expect {
+	e.AssertFunc(assert.Compressed())
+	return e
+}
+
+func decompressor() plugin.Plugin {
+	p := plugin.New()
+	p.SetHandlers(plugin.Handlers{
+		"request": func(ctx *context.Context, h context.Handler) {
+			if ctx.Request.Header.Get("Accept-Encoding") == "" {
+				ctx.Request.Header.Set("Accept-Encoding", strings.Join(Encodings(), ", "))
+			}
+			h.Next(ctx)
+		},
+		"response": func(ctx *context.Context, h context.Handler) {
+			if err := decompressBody(ctx); err != nil {
+				h.Error(ctx, err)
+				return
+			}
+			h.Next(ctx)
+		},
+	})
+	return p
+}
+
+// compressBody replaces the request body with its compressed version.
+func compressBody(req *http.Request, name string) error {
+	enc, err := getEncoding(name)
+	if err != nil {
+		return err
+	}
+
+	body, err := httputil.ReadRequestBody(req)
+	if err != nil || body == nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	writer, err := enc.encoder(buf)
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(body); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	data := buf.Bytes()
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Encoding", name)
+	return nil
+}
+
+// decompressBody replaces the response body with its decoded version,
+// based on the response Content-Encoding header.
+// Multiple encodings are decoded in the reverse order they were applied.
+// The Content-Encoding header is preserved to be asserted.
+func decompressBody(ctx *context.Context) error {
+	res := ctx.Response
+	if res == nil || res.Body == nil || res.Uncompressed {
+		return nil
+	}
+
+	var names []string
+	for _, value := range res.Header["Content-Encoding"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" && !strings.EqualFold(name, "identity") {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	// Leave the response untouched if any encoding is not supported
+	for _, name := range names {
+		if _, err := getEncoding(name); err != nil {
+			return nil
+		}
+	}
+
+	// Streamed bodies, such as Server-Sent Events, are decoded as they are read
+	if har.IsStreaming(res.Header.Get("Content-Type")) {
+		res.Body = &streamDecoder{body: res.Body, names: names}
+		res.ContentLength = -1
+		res.Header.Del("Content-Length")
+		res.Uncompressed = true
+		return nil
+	}
+
+	body, err := httputil.ReadResponseBody(res)
+	if err != nil {
+		return err
+	}
+
+	data := body
+	for i := len(names) - 1; i >= 0 && len(data) > 0; i-- {
+		if data, err = decode(names[i], data); err != nil {
+			return err
+		}
+	}
+
+	res.Body = ioutil.NopCloser(bytes.NewReader(data))
+	res.ContentLength = int64(len(data))
+	res.Header.Del("Content-Length")
+	res.Uncompressed = true
+
+	// Store the compressed body length to be asserted
+	ctx.Set(assert.EncodedLengthKey, len(body))
+	return nil
+}
+
+// streamDecoder decodes the given encoded stream on the first read,
+// so the stream is not consumed until the body is read.
+// Close can be called concurrently to stop a blocked read, like the
+// original body, so decoders are released by the reading goroutine.
+type streamDecoder struct {
+	body    io.ReadCloser
+	names   []string
+	reader  io.Reader
+	closers []io.Closer
+	err     error
+}
+
+func (d *streamDecoder) Read(p []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.reader = d.body
+		for i := len(d.names) - 1; i >= 0; i-- {
+			enc, _ := getEncoding(d.names[i])
+			reader, err := enc.decoder(d.reader)
+			if err != nil {
+				d.fail(fmt.Errorf("cannot decode %s response body: %s", d.names[i], err))
+				break
+			}
+			d.closers = append(d.closers, reader)
+			d.reader = reader
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	n, err := d.reader.Read(p)
+	if err != nil {
+		d.fail(err)
+	}
+	return n, err
+}
+
+// fail stores the given read error and releases the decoders.
+func (d *streamDecoder) fail(err error) {
+	d.err = err
+	for i := len(d.closers) - 1; i >= 0; i-- {
+		d.closers[i].Close()
+	}
+	d.closers = nil
+}
+
+func (d *streamDecoder) Close() error {
+	return d.body.Close()
+}
+
+func decode(name string, data []byte) ([]byte, error) {
+	enc, _ := getEncoding(name)
+	reader, err := enc.decoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s response body: %s", name, err)
+	}
+	defer reader.Close()
+
+	decoded, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s response body: %s", name, err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,214 @@
+package baloo
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+var (
+	compressedMessage = strings.Repeat("hello world ", 20)
+	compressedBody    = `{"message":"` + compressedMessage + `"}`
+)
+
+func createCompressServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Accept-Encoding", r.Header.Get("Accept-Encoding"))
+
+		buf := &bytes.Buffer{}
+		var writer io.WriteCloser
+		switch name {
+		case "raw-deflate":
+			writer, _ = flate.NewWriter(buf, flate.DefaultCompression)
+			name = "deflate"
+		case "identity":
+			w.Write([]byte(compressedBody))
+			return
+		default:
+			enc, err := getEncoding(name)
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			writer, _ = enc.encoder(buf)
+		}
+		writer.Write([]byte(compressedBody))
+		writer.Close()
+
+		w.Header().Set("Content-Encoding", name)
+		w.Write(buf.Bytes())
+	}))
+}
+
+func TestDecompress(t *testing.T) {
+	ts := createCompressServer()
+	defer ts.Close()
+
+	for _, name := range []string{"gzip", "deflate", "raw-deflate"} {
+		New(ts.URL).Decompress().Get("/"+name).
+			Expect(t).
+			Status(200).
+			HeaderEquals("X-Accept-Encoding", "deflate, gzip").
+			ContentEncoding(strings.TrimPrefix(name, "raw-")).
+			BodyEquals(compressedBody).
+			BodyLength(len(compressedBody)).
+			JSON(map[string]string{"message": compressedMessage}).
+			Compressed().
+			Done()
+	}
+}
+
+func TestDecompressStream(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Content-Encoding", "gzip")
+		writer := gzip.NewWriter(w)
+		for i := 1; ; i++ {
+			if _, err := fmt.Fprintf(writer, "event: tick\nid: %d\ndata: %d\n\n", i, i); err != nil {
+				return
+			}
+			writer.Flush()
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+	defer ts.Close()
+
+	// The open stream is decoded as it is read
+	New(ts.URL).Decompress().Get("/").
+		Expect(t).
+		Status(200).
+		ContentEncoding("gzip").
+		SSE().
+		Timeout(time.Second).
+		Event("tick").ID("1").
+		Event("tick").ID("2").
+		Done()
+}
+
+func TestDecompressIdentity(t *testing.T) {
+	ts := createCompressServer()
+	defer ts.Close()
+
+	New(ts.URL).Get("/identity").
+		Decompress().
+		Expect(t).
+		ContentEncoding("identity").
+		BodyEquals(compressedBody).
+		Done()
+}
+
+func TestDecompressCompressedLength(t *testing.T) {
+	ts := createCompressServer()
+	defer ts.Close()
+
+	buf := &bytes.Buffer{}
+	st.Expect(t, compressBuffer(buf, "gzip"), nil)
+
+	New(ts.URL).Get("/gzip").
+		Decompress().
+		Expect(t).
+		CompressedLength(buf.Len()).
+		Done()
+}
+
+func TestDecompressDisabled(t *testing.T) {
+	ts := createCompressServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	New(ts.URL).Get("/gzip").
+		SetHeader("Accept-Encoding", "gzip").
+		Expect(mock).
+		ContentEncoding("gzip").
+		BodyEquals(compressedBody).
+		Done()
+	st.Expect(t, mock.failed, true)
+}
+
+func TestCompress(t *testing.T) {
+	for _, name := range Encodings() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			enc, _ := getEncoding(r.Header.Get("Content-Encoding"))
+			reader, err := enc.decoder(r.Body)
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			body, _ := ioutil.ReadAll(reader)
+			w.Write(body)
+		}))
+
+		New(ts.URL).Post("/").
+			JSON(map[string]string{"foo": "bar"}).
+			Compress(name).
+			Expect(t).
+			Status(200).
+			BodyEquals(`{"foo":"bar"}`).
+			Done()
+		ts.Close()
+	}
+}
+
+func TestCompressUnsupported(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	_, err := New(ts.URL).Post("/").BodyString("foo").Compress("foo").Send()
+	st.Reject(t, err, nil)
+	st.Expect(t, strings.Contains(err.Error(), "unsupported content encoding: foo"), true)
+}
+
+func TestRegisterEncoding(t *testing.T) {
+	defer func() {
+		encodingsMutex.Lock()
+		delete(encodings, "custom")
+		encodingsMutex.Unlock()
+	}()
+
+	RegisterEncoding("custom", func(w io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	}, func(r io.Reader) (io.ReadCloser, error) {
+		return ioutil.NopCloser(r), nil
+	})
+	st.Expect(t, Encodings(), []string{"custom", "deflate", "gzip"})
+
+	buf := &bytes.Buffer{}
+	st.Expect(t, compressBuffer(buf, "custom"), nil)
+	st.Expect(t, buf.String(), compressedBody)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func compressBuffer(buf *bytes.Buffer, name string) error {
+	enc, err := getEncoding(name)
+	if err != nil {
+		return err
+	}
+	writer, err := enc.encoder(buf)
+	if err != nil {
+		return err
+	}
+	writer.Write([]byte(compressedBody))
+	return writer.Close()
+}
@@ -0,0 +1,130 @@
+package baloo
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// Curl returns the equivalent curl command of the current request,
+// based on the final HTTP request after all the middleware has run.
+// If the request was not sent yet, the middleware is executed in a
+// deep copy of the request, leaving the request headers, cookies and client untouched.
+// Note that io.Reader based bodies will be consumed.
+// Once sent, the sensitive header values are redacted as in HTTP exchange dumps.
+func (r *Request) Curl() (string, error) {
+	if r.sent != nil {
+		return curlCommand(r.sent, r.redactedHeaders()...), nil
+	}
+
+	ctx, err := r.Prepare()
+	if err != nil {
+		return "", err
+	}
+	// Read the captured body stream, as it is not sent
+	if capture, ok := ctx.Request.Body.(*bodyCapture); ok {
+		if _, err := io.Copy(ioutil.Discard, capture); err != nil {
+			return "", err
+		}
+	}
+	return curlCommand(ctx), nil
+}
+
+// curlCommand returns the curl command of the HTTP request
+// stored in the given context, redacting the given header fields.
+func curlCommand(ctx *context.Context, redact ...string) string {
+	req := ctx.Request
+	args := []string{"curl"}
+
+	switch req.Method {
+	case "", "GET":
+	case "HEAD":
+		args = append(args, "--head")
+	default:
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, shellQuote(req.URL.String()))
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		args = append(args, "-H", shellQuote("Host: "+req.Host))
+	}
+
+	for _, key := range httputil.SortedKeys(req.Header) {
+		if strings.EqualFold(key, "Content-Length") {
+			continue
+		}
+		for _, value := range req.Header[key] {
+			if httputil.IsRedacted(key, redact) {
+				value = httputil.Redacted
+			}
+			args = append(args, "-H", shellQuote(key+": "+value))
+		}
+	}
+
+	// Include cookies stored in the client cookie jar
+	if ctx.Client != nil && ctx.Client.Jar != nil {
+		for _, cookie := range ctx.Client.Jar.Cookies(req.URL) {
+			value := cookie.String()
+			if httputil.IsRedacted("Cookie", redact) {
+				value = cookie.Name + "=" + httputil.Redacted
+			}
+			args = append(args, "-b", shellQuote(value))
+		}
+	}
+
+	if body := requestBody(ctx); len(body) > 0 {
+		args = append(args, "--data-binary", shellQuote(string(body)))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes the given string to be safely used as shell argument.
+// Strings containing non-printable characters use ANSI-C quoting.
+func shellQuote(s string) string {
+	printable := true
+	for _, c := range s {
+		if c == 0xFFFD || (c < 0x20 && c != '\n' && c != '\t') || c == 0x7F {
+			printable = false
+			break
+		}
+	}
+
+	if printable {
+		return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+	}
+
+	buf := &strings.Builder{}
+	buf.WriteString("$'")
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '\'':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7F:
+			fmt.Fprintf(buf, `\x%02x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteString("'")
+	return buf.String()
+}
+
+// reproduceCommand returns the curl command of the sent HTTP request, if present.
+func reproduceCommand(ctx *context.Context, redact []string) string {
+	if ctx == nil || ctx.Request == nil || ctx.Request.URL == nil {
+		return ""
+	}
+	return curlCommand(ctx, append(append([]string(nil), redact...), RedactedHeaders...)...)
+}
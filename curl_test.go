@@ -0,0 +1,126 @@
+package baloo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestRequestCurl(t *testing.T) {
+	cli := New("http://foo.com").SetHeader("Client", "baloo")
+	req := cli.Post("/users/:id").
+		Param("id", "123").
+		SetQuery("foo", "bar").
+		AddCookie(&http.Cookie{Name: "session", Value: "abc"}).
+		JSON(map[string]string{"name": "it's me"})
+
+	cmd, err := req.Curl()
+	st.Expect(t, err, nil)
+	st.Expect(t, cmd, `curl -X POST 'http://foo.com/users/123?foo=bar'`+
+		` -H 'Client: baloo'`+
+		` -H 'Content-Type: application/json'`+
+		` -H 'Cookie: session=abc'`+
+		` -H 'User-Agent: baloo/`+Version+`'`+
+		` --data-binary '{"name":"it'\''s me"}`+"\n"+`'`)
+
+	// Curl must not have side-effects in the request
+	cmd2, _ := req.Curl()
+	st.Expect(t, cmd2, cmd)
+}
+
+func TestRequestCurlThenSend(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Cookie", r.Header.Get("Cookie"))
+		w.Header().Set("X-Count", strconv.Itoa(len(r.Header["Cookie"])))
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	req := New(ts.URL).Post("/").
+		AddCookie(&http.Cookie{Name: "session", Value: "abc"}).
+		BodyString("hello")
+
+	_, err := req.Curl()
+	st.Expect(t, err, nil)
+	_, err = req.Curl()
+	st.Expect(t, err, nil)
+
+	res, err := req.Send()
+	st.Expect(t, err, nil)
+	st.Expect(t, res.Header.Get("X-Cookie"), "session=abc")
+	st.Expect(t, res.Header.Get("X-Count"), "1")
+	st.Expect(t, res.String(), "hello")
+}
+
+func TestRequestCurlSent(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	req := New(ts.URL).Put("/foo").BodyString("hello")
+	_, err := req.Send()
+	st.Expect(t, err, nil)
+
+	cmd, err := req.Curl()
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.HasPrefix(cmd, "curl -X PUT '"+ts.URL+"/foo'"), true)
+	st.Expect(t, strings.HasSuffix(cmd, "--data-binary 'hello'"), true)
+}
+
+func TestRequestCurlSentRedacted(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	req := New(ts.URL).RedactHeaders("X-Token").Get("/foo").
+		SetHeader("Authorization", "Bearer secret").
+		SetHeader("X-Token", "secret")
+	_, err := req.Send()
+	st.Expect(t, err, nil)
+
+	cmd, err := req.Curl()
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(cmd, "-H 'Authorization: [REDACTED]'"), true)
+	st.Expect(t, strings.Contains(cmd, "-H 'X-Token: [REDACTED]'"), true)
+	st.Expect(t, strings.Contains(cmd, "secret"), false)
+}
+
+func TestRequestCurlMultipart(t *testing.T) {
+	req := New("http://foo.com").Post("/upload").
+		File("file.txt", bytes.NewReader([]byte("hello world")))
+
+	cmd, err := req.Curl()
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(cmd, "-H 'Content-Type: multipart/form-data; boundary="), true)
+	st.Expect(t, strings.Contains(cmd, "--data-binary $'--"), true)
+	st.Expect(t, strings.Contains(cmd, `filename="file.txt"\r\n`), true)
+	st.Expect(t, strings.Contains(cmd, `hello world\r\n`), true)
+}
+
+func TestShellQuote(t *testing.T) {
+	st.Expect(t, shellQuote("foo bar"), "'foo bar'")
+	st.Expect(t, shellQuote("it's"), `'it'\''s'`)
+	st.Expect(t, shellQuote("foo\nbar"), "'foo\nbar'")
+	st.Expect(t, shellQuote("foo\r\n'bar'\x00"), `$'foo\r\n\'bar\'\x00'`)
+}
+
+func TestExpectReproduceCommand(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	New(ts.URL).Get("/foo").
+		SetHeader("Authorization", "Bearer secret").
+		Expect(mock).
+		Status(404).
+		Done()
+
+	st.Expect(t, mock.failed, true)
+	logs := strings.Join(mock.logs, "\n")
+	st.Expect(t, strings.Contains(logs, "reproduce with:\ncurl '"+ts.URL+"/foo'"), true)
+	st.Expect(t, strings.Contains(logs, "-H 'Authorization: [REDACTED]'"), true)
+}
@@ -0,0 +1,208 @@
+package baloo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/h2non/baloo.v3/har"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// DebugEnv defines the environment variable used to enable debugging globally.
+// Use "1" to dump the HTTP exchange on failure and "verbose" to log every exchange.
+const DebugEnv = "BALOO_DEBUG"
+
+// RedactedHeaders stores the header fields whose values
+// are redacted in HTTP request/response dumps.
+var RedactedHeaders = append([]string(nil), httputil.RedactedHeaders...)
+
+// MaxDumpBodySize defines the maximum body size in bytes
+// printed in HTTP request/response dumps.
+var MaxDumpBodySize = 4096
+
+// debugOptions stores the HTTP exchange debugging options.
+type debugOptions struct {
+	dump    bool
+	verbose bool
+	redact  []string
+}
+
+// envDebugOptions returns the debugging options defined via environment variable.
+func envDebugOptions() debugOptions {
+	switch strings.ToLower(os.Getenv(DebugEnv)) {
+	case "", "0", "false":
+		return debugOptions{}
+	case "verbose":
+		return debugOptions{dump: true, verbose: true}
+	default:
+		return debugOptions{dump: true}
+	}
+}
+
+// merge merges the given options into the current ones.
+func (o debugOptions) merge(opts debugOptions) debugOptions {
+	o.dump = o.dump || opts.dump
+	o.verbose = o.verbose || opts.verbose
+	o.redact = append(append([]string(nil), o.redact...), opts.redact...)
+	return o
+}
+
+// errBodyNotRead is returned when reading again a request body not fully sent yet.
+var errBodyNotRead = errors.New("baloo: request body was not fully read yet")
+
+// bodyCapture captures the bytes read from a request body stream.
+type bodyCapture struct {
+	io.ReadCloser
+	mutex sync.Mutex
+	buf   bytes.Buffer
+	eof   bool
+}
+
+// Read reads from the body stream, capturing the read bytes.
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mutex.Lock()
+	b.buf.Write(p[:n])
+	b.eof = b.eof || err == io.EOF
+	b.mutex.Unlock()
+	return n, err
+}
+
+// GetBody returns a new reader of the captured body,
+// once the body stream was read until the end.
+func (b *bodyCapture) GetBody() (io.ReadCloser, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !b.eof {
+		return nil, errBodyNotRead
+	}
+	return ioutil.NopCloser(bytes.NewReader(b.buf.Bytes())), nil
+}
+
+// captureRequestBody captures the outgoing request body stream while the
+// transport sends it, if it cannot be read again via GetBody, so the final
+// request body can be read again once sent without buffering it in advance.
+// It is registered once when the request is created.
+func captureRequestBody(ctx *context.Context, h context.Handler) {
+	req := ctx.Request
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		capture := &bodyCapture{ReadCloser: req.Body}
+		req.Body = capture
+		req.GetBody = capture.GetBody
+	}
+	h.Next(ctx)
+}
+
+// bufferBody reads the given request body, if it cannot be read again
+// via GetBody, re-filling the body stream and defining GetBody.
+func bufferBody(req *http.Request) error {
+	if req.GetBody != nil {
+		return nil
+	}
+	_, err := httputil.ReadRequestBody(req)
+	return err
+}
+
+// requestBody returns the final outgoing request body of the given context, if present.
+// Middleware replacing the request body, such as compression, defines GetBody accordingly.
+func requestBody(ctx *context.Context) []byte {
+	if ctx == nil || ctx.Request == nil || ctx.Request.GetBody == nil {
+		return nil
+	}
+	reader, err := ctx.Request.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer reader.Close()
+	body, _ := ioutil.ReadAll(reader)
+	return body
+}
+
+// streamingBodyOmitted is printed in place of streamed response bodies,
+// such as Server-Sent Events, which are not read as they may never end.
+const streamingBodyOmitted = "<streaming body omitted>"
+
+// readResponseBody reads the response body, re-filling the body stream.
+// Streamed response bodies are not read.
+func readResponseBody(res *http.Response) []byte {
+	if res == nil {
+		return nil
+	}
+	if har.IsStreaming(res.Header.Get("Content-Type")) {
+		return []byte(streamingBodyOmitted)
+	}
+	body, _ := httputil.ReadResponseBody(res)
+	return body
+}
+
+// Dump returns a human-friendly dump of the given HTTP request and response,
+// redacting the sensitive header fields.
+func Dump(res *gentleman.Response, redact ...string) string {
+	buf := &bytes.Buffer{}
+	redact = append(append([]string(nil), redact...), RedactedHeaders...)
+
+	if req := res.RawRequest; req != nil {
+		fmt.Fprintf(buf, "> %s %s %s\n", req.Method, req.URL, req.Proto)
+		if req.Host != "" && req.Header.Get("Host") == "" {
+			fmt.Fprintf(buf, "> Host: %s\n", req.Host)
+		}
+		dumpHeaders(buf, "> ", req.Header, redact)
+		dumpBody(buf, "> ", req.Header, requestBody(res.Context))
+	}
+
+	if r := res.RawResponse; r != nil && r.StatusCode != 0 {
+		fmt.Fprintf(buf, "< %s %s\n", r.Proto, r.Status)
+		dumpHeaders(buf, "< ", r.Header, redact)
+		dumpBody(buf, "< ", r.Header, readResponseBody(r))
+	}
+
+	return buf.String()
+}
+
+func dumpHeaders(buf *bytes.Buffer, prefix string, header http.Header, redact []string) {
+	for _, key := range httputil.SortedKeys(header) {
+		for _, value := range header[key] {
+			if httputil.IsRedacted(key, redact) {
+				value = httputil.Redacted
+			}
+			fmt.Fprintf(buf, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+func dumpBody(buf *bytes.Buffer, prefix string, header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	if strings.Contains(header.Get("Content-Type"), "json") {
+		pretty := &bytes.Buffer{}
+		if err := json.Indent(pretty, body, "", "  "); err == nil {
+			body = pretty.Bytes()
+		}
+	}
+
+	var truncated int
+	if MaxDumpBodySize > 0 && len(body) > MaxDumpBodySize {
+		truncated = len(body) - MaxDumpBodySize
+		body = body[:MaxDumpBodySize]
+	}
+
+	fmt.Fprintf(buf, "%s\n", prefix)
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		fmt.Fprintf(buf, "%s%s\n", prefix, line)
+	}
+	if truncated > 0 {
+		fmt.Fprintf(buf, "%s... (%d bytes truncated)\n", prefix, truncated)
+	}
+}
@@ -0,0 +1,182 @@
+package baloo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+type testingMock struct {
+	failed bool
+	errors []string
+	logs   []string
+}
+
+func (t *testingMock) Error(args ...interface{}) {
+	t.failed = true
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *testingMock) Fail() {
+	t.failed = true
+}
+
+func (t *testingMock) Logf(format string, args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+func createJSONServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintln(w, `{"foo":"bar"}`)
+	}))
+}
+
+func TestDumpOnFailure(t *testing.T) {
+	ts := createJSONServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	cli := New(ts.URL).DumpOnFailure(true).RedactHeaders("X-Token")
+	cli.Post("/foo").
+		SetHeader("Authorization", "Bearer secret").
+		SetHeader("X-Token", "secret").
+		JSON(map[string]string{"hello": "world"}).
+		Expect(mock).
+		Status(404).
+		Done()
+
+	st.Expect(t, mock.failed, true)
+	dump := strings.Join(mock.logs, "\n")
+	st.Expect(t, strings.Contains(dump, "> POST "+ts.URL+"/foo"), true)
+	st.Expect(t, strings.Contains(dump, "> Authorization: [REDACTED]"), true)
+	st.Expect(t, strings.Contains(dump, "> X-Token: [REDACTED]"), true)
+	st.Expect(t, strings.Contains(dump, `>   "hello": "world"`), true)
+	st.Expect(t, strings.Contains(dump, "< HTTP/1.1 200 OK"), true)
+	st.Expect(t, strings.Contains(dump, "< Set-Cookie: [REDACTED]"), true)
+	st.Expect(t, strings.Contains(dump, `<   "foo": "bar"`), true)
+	st.Expect(t, strings.Contains(dump, "secret"), false)
+}
+
+func TestDumpOnSuccess(t *testing.T) {
+	ts := createJSONServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	New(ts.URL).DumpOnFailure(true).Get("/foo").Expect(mock).Status(200).Done()
+	st.Expect(t, mock.failed, false)
+	st.Expect(t, len(mock.logs), 0)
+}
+
+func TestVerbose(t *testing.T) {
+	ts := createJSONServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	parent := New(ts.URL).Verbose(true)
+	New(ts.URL).UseParent(parent).Get("/foo").Expect(mock).Status(200).JSON(map[string]string{"foo": "bar"}).Done()
+	st.Expect(t, mock.failed, false)
+	st.Expect(t, len(mock.logs), 1)
+	st.Expect(t, strings.Contains(mock.logs[0], "> GET "+ts.URL+"/foo"), true)
+	st.Expect(t, strings.Contains(mock.logs[0], `<   "foo": "bar"`), true)
+}
+
+func TestDumpOnFailureStreaming(t *testing.T) {
+	stop := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: hello\n\n")
+		w.(http.Flusher).Flush()
+		<-stop
+	}))
+	defer ts.Close()
+	defer close(stop)
+
+	// The never ending stream must not be read by the dump
+	mock := &testingMock{}
+	done := make(chan struct{})
+	go func() {
+		New(ts.URL).DumpOnFailure(true).Get("/").Expect(mock).Status(404).Done()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("dump read the streamed response body")
+	}
+
+	st.Expect(t, mock.failed, true)
+	dump := strings.Join(mock.logs, "\n")
+	st.Expect(t, strings.Contains(dump, "< Content-Type: text/event-stream"), true)
+	st.Expect(t, strings.Contains(dump, "< "+streamingBodyOmitted), true)
+}
+
+func TestDumpStreamedRequestBody(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	req := New(ts.URL).Verbose(true).Post("/foo").Body(strings.NewReader("hello world"))
+	req.Expect(mock).Status(200).Done()
+	st.Expect(t, len(mock.logs), 1)
+	st.Expect(t, strings.Contains(mock.logs[0], "> hello world"), true)
+
+	// The request body stream is captured while sent instead of buffered
+	_, ok := req.sent.Request.Body.(*bodyCapture)
+	st.Expect(t, ok, true)
+
+	capture := &bodyCapture{ReadCloser: ioutil.NopCloser(strings.NewReader("hello"))}
+	_, err := capture.GetBody()
+	st.Expect(t, err, errBodyNotRead)
+}
+
+func TestDebugEnv(t *testing.T) {
+	defer os.Unsetenv(DebugEnv)
+
+	os.Setenv(DebugEnv, "1")
+	st.Expect(t, envDebugOptions(), debugOptions{dump: true})
+	os.Setenv(DebugEnv, "verbose")
+	st.Expect(t, envDebugOptions(), debugOptions{dump: true, verbose: true})
+	os.Setenv(DebugEnv, "false")
+	st.Expect(t, envDebugOptions(), debugOptions{})
+}
+
+func TestDumpBodyTruncate(t *testing.T) {
+	defer func(size int) { MaxDumpBodySize = size }(MaxDumpBodySize)
+	MaxDumpBodySize = 5
+
+	mock := &testingMock{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello world")
+	}))
+	defer ts.Close()
+
+	New(ts.URL).Verbose(true).Get("/").Expect(mock).Done()
+	st.Expect(t, len(mock.logs), 1)
+	st.Expect(t, strings.Contains(mock.logs[0], "< hello\n< ... (6 bytes truncated)"), true)
+}
+
+func TestDebugRedactNoAliasing(t *testing.T) {
+	redact := make([]string, 1, 4)
+	redact[0] = "X-Base"
+
+	base := debugOptions{redact: redact}
+	foo := base.merge(debugOptions{redact: []string{"X-Foo"}})
+	bar := base.merge(debugOptions{redact: []string{"X-Bar"}})
+	st.Expect(t, foo.redact, []string{"X-Base", "X-Foo"})
+	st.Expect(t, bar.redact, []string{"X-Base", "X-Bar"})
+
+	res := &gentleman.Response{RawRequest: &http.Request{Method: "GET", URL: &url.URL{Path: "/"}, Header: http.Header{}}}
+	Dump(res, redact...)
+	st.Expect(t, redact[:cap(redact)], []string{"X-Base", "", "", ""})
+}
@@ -0,0 +1,211 @@
+package baloo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// diff deeply compares the given Go values, returning one line
+// per mismatching field, element or map key, annotated with its path.
+func diff(have, want interface{}) []string {
+	var lines []string
+	diffValue("", reflect.ValueOf(have), reflect.ValueOf(want), &lines)
+	return lines
+}
+
+func diffValue(path string, have, want reflect.Value, lines *[]string) {
+	mismatch := func(format string, args ...interface{}) {
+		name := path
+		if name == "" {
+			name = "value"
+		}
+		*lines = append(*lines, name+": "+fmt.Sprintf(format, args...))
+	}
+
+	if !have.IsValid() || !want.IsValid() {
+		// Untyped nil values are equal to nil pointers, maps, slices...
+		if !isNil(have) || !isNil(want) {
+			mismatch("%s != %s", formatValue(have), formatValue(want))
+		}
+		return
+	}
+	if have.Type() != want.Type() {
+		mismatch("type %s != %s", have.Type(), want.Type())
+		return
+	}
+
+	// Values defining their own equality, such as time.Time
+	if equal := have.MethodByName("Equal"); equal.IsValid() {
+		kind := equal.Type()
+		if kind.NumIn() == 1 && kind.In(0) == want.Type() && kind.NumOut() == 1 && kind.Out(0).Kind() == reflect.Bool {
+			if !equal.Call([]reflect.Value{want})[0].Bool() {
+				mismatch("%s != %s", formatValue(have), formatValue(want))
+			}
+			return
+		}
+	}
+
+	switch have.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if have.IsNil() || want.IsNil() {
+			if have.IsNil() != want.IsNil() {
+				mismatch("%s != %s", formatValue(have), formatValue(want))
+			}
+			return
+		}
+		diffValue(path, have.Elem(), want.Elem(), lines)
+	case reflect.Struct:
+		for i := 0; i < have.NumField(); i++ {
+			field := have.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			diffValue(joinPath(path, field.Name), have.Field(i), want.Field(i), lines)
+		}
+	case reflect.Slice, reflect.Array:
+		if have.Len() != want.Len() {
+			mismatch("length %d != %d", have.Len(), want.Len())
+		}
+		for i := 0; i < have.Len() && i < want.Len(); i++ {
+			diffValue(fmt.Sprintf("%s[%d]", path, i), have.Index(i), want.Index(i), lines)
+		}
+	case reflect.Map:
+		keys := append(have.MapKeys(), want.MapKeys()...)
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		seen := map[interface{}]bool{}
+		for _, key := range keys {
+			if seen[key.Interface()] {
+				continue
+			}
+			seen[key.Interface()] = true
+			name := fmt.Sprintf("%s[%v]", path, key.Interface())
+			haveValue, wantValue := have.MapIndex(key), want.MapIndex(key)
+			switch {
+			case !haveValue.IsValid():
+				*lines = append(*lines, name+": missing")
+			case !wantValue.IsValid():
+				*lines = append(*lines, name+": unexpected "+formatValue(haveValue))
+			default:
+				diffValue(name, haveValue, wantValue, lines)
+			}
+		}
+	default:
+		if !reflect.DeepEqual(have.Interface(), want.Interface()) {
+			mismatch("%s != %s", formatValue(have), formatValue(want))
+		}
+	}
+}
+
+// lookupField resolves the given dot-separated path of struct field names,
+// JSON field names, map keys or slice indexes, such as "Address.City" or "tags.0".
+func lookupField(value reflect.Value, path string) (reflect.Value, error) {
+	for _, name := range strings.Split(path, ".") {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return value, fmt.Errorf("field '%s' not found: nil value", path)
+			}
+			value = value.Elem()
+		}
+
+		var next reflect.Value
+		switch value.Kind() {
+		case reflect.Struct:
+			next = structField(value, name)
+		case reflect.Map:
+			if value.Type().Key().Kind() == reflect.String {
+				next = value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+			}
+		case reflect.Slice, reflect.Array:
+			if index, err := strconv.Atoi(name); err == nil && index >= 0 && index < value.Len() {
+				next = value.Index(index)
+			}
+		}
+		if !next.IsValid() {
+			return next, fmt.Errorf("field '%s' not found", path)
+		}
+		value = next
+	}
+	return value, nil
+}
+
+// structField returns the exported struct field matching the given Go or JSON field name.
+func structField(value reflect.Value, name string) reflect.Value {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.Name == name || (tag != "" && tag == name) {
+			return value.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// convertValue converts the given value to the given type if both are
+// numbers or strings, so untyped constants can be compared with typed fields.
+// Numbers are not converted if the conversion loses information, such as
+// truncated decimals or overflows, so the type mismatch is reported instead.
+func convertValue(value reflect.Value, kind reflect.Type) reflect.Value {
+	if !value.IsValid() || value.Type() == kind || !value.Type().ConvertibleTo(kind) {
+		return value
+	}
+	if value.Kind() == reflect.String && kind.Kind() == reflect.String {
+		return value.Convert(kind)
+	}
+	if isNumber(value.Kind()) && isNumber(kind.Kind()) {
+		converted := value.Convert(kind)
+		if isNegative(value) != isNegative(converted) || converted.Convert(value.Type()).Interface() != value.Interface() {
+			return value
+		}
+		return converted
+	}
+	return value
+}
+
+// isNegative reports whether the given number is negative.
+func isNegative(value reflect.Value) bool {
+	switch {
+	case value.CanInt():
+		return value.Int() < 0
+	case value.CanFloat():
+		return value.Float() < 0
+	}
+	return false
+}
+
+// isNil reports whether the given value is invalid, as untyped nil values,
+// or a nil pointer, interface, map, slice, function or channel.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func formatValue(value reflect.Value) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+	return fmt.Sprintf("%#v", value.Interface())
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
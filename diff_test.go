@@ -0,0 +1,104 @@
+package baloo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+func TestDiff(t *testing.T) {
+	type item struct {
+		Name  string
+		Count int
+		Tags  []string
+		Next  *item
+		Attrs map[string]interface{}
+	}
+
+	st.Expect(t, len(diff(item{Name: "foo"}, item{Name: "foo"})), 0)
+	st.Expect(t, diff(1, 2), []string{"value: 1 != 2"})
+	st.Expect(t, diff(1, "1"), []string{"value: type int != string"})
+
+	have := item{Name: "foo", Tags: []string{"a"}, Next: &item{Count: 1}, Attrs: map[string]interface{}{"x": 1.0}}
+	want := item{Name: "bar", Tags: []string{"a", "b"}, Attrs: map[string]interface{}{"y": true}}
+	st.Expect(t, diff(have, want), []string{
+		`Name: "foo" != "bar"`,
+		"Tags: length 1 != 2",
+		"Next: &baloo.item{Name:\"\", Count:1, Tags:[]string(nil), Next:(*baloo.item)(nil), Attrs:map[string]interface {}(nil)} != (*baloo.item)(nil)",
+		"Attrs[x]: unexpected 1",
+		"Attrs[y]: missing",
+	})
+}
+
+func TestDiffNil(t *testing.T) {
+	type item struct {
+		Next *item
+	}
+
+	st.Expect(t, len(diff(nil, nil)), 0)
+	st.Expect(t, len(diff((*item)(nil), nil)), 0)
+	st.Expect(t, len(diff(nil, []string(nil))), 0)
+	st.Expect(t, len(diff(map[string]int(nil), nil)), 0)
+	st.Expect(t, diff(&item{}, nil), []string{"value: &baloo.item{Next:(*baloo.item)(nil)} != nil"})
+	st.Expect(t, diff(0, nil), []string{"value: 0 != nil"})
+	st.Expect(t, diff(nil, []string{}), []string{"value: nil != []string{}"})
+}
+
+func TestDiffEqualMethod(t *testing.T) {
+	now := time.Now()
+	st.Expect(t, len(diff(now, now.UTC())), 0)
+	st.Expect(t, len(diff(now, now.Add(time.Second))), 1)
+}
+
+func TestLookupField(t *testing.T) {
+	type inner struct {
+		City string `json:"city"`
+	}
+	type outer struct {
+		Inner *inner          `json:"inner"`
+		List  []int           `json:"list"`
+		Map   map[string]bool `json:"map"`
+	}
+	value := outer{Inner: &inner{City: "foo"}, List: []int{1, 2}, Map: map[string]bool{"ok": true}}
+
+	for path, expected := range map[string]interface{}{
+		"Inner.City": "foo",
+		"inner.city": "foo",
+		"list.1":     2,
+		"Map.ok":     true,
+	} {
+		field, err := lookupField(reflect.ValueOf(value), path)
+		st.Expect(t, err, nil)
+		st.Expect(t, field.Interface(), expected)
+	}
+
+	_, err := lookupField(reflect.ValueOf(value), "list.5")
+	st.Expect(t, err.Error(), "field 'list.5' not found")
+	_, err = lookupField(reflect.ValueOf(outer{}), "Inner.City")
+	st.Expect(t, err.Error(), "field 'Inner.City' not found: nil value")
+}
+
+func TestConvertValue(t *testing.T) {
+	type myString string
+	for _, c := range []struct {
+		value    interface{}
+		kind     interface{}
+		expected interface{}
+	}{
+		{1, float64(0), float64(1)},
+		{1.0, 0, 1},
+		{200, uint8(0), uint8(200)},
+		{"foo", myString(""), myString("foo")},
+		// Lossy conversions are not performed
+		{1.5, 0, 1.5},
+		{300, uint8(0), 300},
+		{-1, uint(0), -1},
+		{uint64(1 << 63), int64(0), uint64(1 << 63)},
+		{"foo", 0, "foo"},
+	} {
+		value := convertValue(reflect.ValueOf(c.value), reflect.TypeOf(c.kind))
+		st.Expect(t, value.Interface(), c.expected)
+	}
+}
@@ -0,0 +1,31 @@
+// Package brotli implements the br (brotli) content encoding, used to compress
+// request bodies and decompress response bodies.
+// Import it for its side effects to enable brotli encoded bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/encoding/brotli"
+package brotli
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/andybalholm/brotli"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+// Name stores the content encoding name.
+const Name = "br"
+
+// Encoder creates a writer compressing the data written to the given stream.
+func Encoder(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriter(w), nil
+}
+
+// Decoder creates a reader decompressing the given stream.
+func Decoder(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(brotli.NewReader(r)), nil
+}
+
+func init() {
+	baloo.RegisterEncoding(Name, Encoder, Decoder)
+}
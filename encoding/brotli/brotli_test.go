@@ -0,0 +1,48 @@
+package brotli
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+var body = strings.Repeat("hello world ", 20)
+
+func TestRegister(t *testing.T) {
+	st.Expect(t, strings.Contains(strings.Join(baloo.Encodings(), ","), Name), true)
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Echo the decompressed request body as compressed response body
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader, err := Decoder(r.Body)
+		if err != nil || r.Header.Get("Content-Encoding") != Name {
+			w.WriteHeader(400)
+			return
+		}
+		data, _ := ioutil.ReadAll(reader)
+		reader.Close()
+
+		w.Header().Set("Content-Encoding", Name)
+		writer, _ := Encoder(w)
+		writer.Write(data)
+		writer.Close()
+	}))
+	defer ts.Close()
+
+	baloo.New(ts.URL).Decompress().
+		Post("/").
+		BodyString(body).
+		Compress(Name).
+		Expect(t).
+		Status(200).
+		ContentEncoding(Name).
+		Compressed().
+		BodyEquals(body).
+		Done()
+}
@@ -0,0 +1,35 @@
+// Package zstd implements the zstd (Zstandard) content encoding, used to compress
+// request bodies and decompress response bodies.
+// Import it for its side effects to enable Zstandard encoded bodies:
+//
+//	import _ "gopkg.in/h2non/baloo.v3/encoding/zstd"
+package zstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+// Name stores the content encoding name.
+const Name = "zstd"
+
+// Encoder creates a writer compressing the data written to the given stream.
+func Encoder(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+// Decoder creates a reader decompressing the given stream.
+// The decoder is released once the reader is closed.
+func Decoder(r io.Reader) (io.ReadCloser, error) {
+	reader, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return reader.IOReadCloser(), nil
+}
+
+func init() {
+	baloo.RegisterEncoding(Name, Encoder, Decoder)
+}
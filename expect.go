@@ -7,22 +7,40 @@ import (
 	"runtime"
 
 	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/baloo.v3/har"
 	"gopkg.in/h2non/gentleman.v2"
 )
 
 // Assertions stores global assertion functions by alias name.
 // Use Expect.Assert('<assertion name>') to use
 // new assertion at request expectation level.
+//
+// Deprecated: writing to this map is not safe for concurrent use.
+// Use AddAssertFunc() instead, which registers the assertion function in
+// the DefaultRegistry. Functions stored in this map are still resolved
+// if no assertion function is registered by the same alias.
 var Assertions = make(map[string]assert.Func)
 
+// DefaultRegistry stores the global assertion functions by alias name,
+// inherited by the assertion registry of every Client.
+var DefaultRegistry = NewRegistry(nil)
+
 // AddAssertFunc adds a new assertion function at global level by alias name.
 // Then you can trigger the assertion function in any expectation test.
 func AddAssertFunc(name string, fn assert.Func) {
-	Assertions[name] = fn
+	DefaultRegistry.Add(name, fn)
+}
+
+// AddAssertFactory adds a new parameterized assertion function
+// at global level by alias name.
+// Arguments are passed via Expect.AssertWith("alias", args...).
+func AddAssertFactory(name string, factory AssertFactory) {
+	DefaultRegistry.AddFactory(name, factory)
 }
 
 // FlushAssertFuncs flushes registered assertion functions.
 func FlushAssertFuncs() {
+	DefaultRegistry.Flush()
 	Assertions = make(map[string]assert.Func)
 }
 
@@ -37,8 +55,10 @@ type TestingT interface {
 // able to define multiple assertion functions to match the response.
 type Expect struct {
 	test       TestingT
+	negate     bool
 	request    *Request
 	assertions []assert.Func
+	binders    []assert.Func
 }
 
 // NewExpect creates a new testing expectation instance.
@@ -51,6 +71,10 @@ func NewExpect(req *Request) *Expect {
 // be supported via adapters.
 func (e *Expect) BindTest(t TestingT) *Expect {
 	e.test = t
+	// Store the test name, used to annotate recorded exchanges
+	if test, ok := t.(interface{ Name() string }); ok {
+		e.request.Request.Context.Set(har.CommentKey, test.Name())
+	}
 	return e
 }
 
@@ -138,8 +162,15 @@ func (e *Expect) RedirectTo(uri string) *Expect {
 	return e
 }
 
+// Charset asserts the response Content-Type charset, such as "utf-8" or "ISO-8859-1".
+func (e *Expect) Charset(name string) *Expect {
+	e.AssertFunc(assert.Charset(name))
+	return e
+}
+
 // BodyEquals asserts as strict equality comparison the
 // response body with a given string string.
+// The body is decoded based on the response charset.
 func (e *Expect) BodyEquals(pattern string) *Expect {
 	e.AssertFunc(assert.BodyEquals(pattern))
 	return e
@@ -147,6 +178,7 @@ func (e *Expect) BodyEquals(pattern string) *Expect {
 
 // BodyMatchString asserts a response body matching a string expression.
 // Regular expressions can be used as value to perform the specific assertions.
+// The body is decoded based on the response charset.
 func (e *Expect) BodyMatchString(pattern string) *Expect {
 	e.AssertFunc(assert.BodyMatchString(pattern))
 	return e
@@ -164,6 +196,14 @@ func (e *Expect) JSON(data interface{}) *Expect {
 	return e
 }
 
+// Decoded asserts the response body decoded with the codec registered
+// for the response Content-Type, such as protobuf, MessagePack, CBOR or YAML,
+// with the given structure.
+func (e *Expect) Decoded(data interface{}) *Expect {
+	e.AssertFunc(assert.Decoded(data))
+	return e
+}
+
 // JSONSchema asserts the response body with the given
 // JSON schema definition.
 func (e *Expect) JSONSchema(schema string) *Expect {
@@ -171,26 +211,106 @@ func (e *Expect) JSONSchema(schema string) *Expect {
 	return e
 }
 
-// Assert adds a new assertion function by alias name.
-// Assertion function must be previosly registered
-// via baloo.AddAssertFunc("alias", function).
+// BindJSON decodes the JSON response body into the given value,
+// such as a struct pointer, once all the assertions pass.
+// Unknown fields are ignored.
+func (e *Expect) BindJSON(out interface{}) *Expect {
+	e.negate = false
+	e.binders = append(e.binders, assert.BindJSON(out))
+	return e
+}
+
+// BindJSONStrict decodes the JSON response body into the given value
+// once all the assertions pass, failing if the body contains fields
+// not defined by the destination struct.
+func (e *Expect) BindJSONStrict(out interface{}) *Expect {
+	e.negate = false
+	e.binders = append(e.binders, assert.BindJSONStrict(out))
+	return e
+}
+
+// BindXML decodes the XML response body into the given value,
+// such as a struct pointer, once all the assertions pass.
+func (e *Expect) BindXML(out interface{}) *Expect {
+	e.negate = false
+	e.binders = append(e.binders, assert.BindXML(out))
+	return e
+}
+
+// Assert adds new assertion functions by alias name.
+// Assertion function must be previously registered
+// via baloo.AddAssertFunc("alias", function) or at Client level.
 func (e *Expect) Assert(assertions ...string) *Expect {
 	for _, alias := range assertions {
-		fn, ok := Assertions[alias]
-		if !ok {
-			panic("No assertion function registered by alias: " + alias)
-		}
-		e.assertions = append(e.assertions, fn)
+		e.AssertFunc(e.assertion(alias))
 	}
 	return e
 }
 
+// AssertWith adds a new parameterized assertion function by alias name
+// with the given arguments, such as AssertWith("hasItems", 3).
+// Assert only accepts alias names, so it remains backwards compatible.
+// Assertion function must be previously registered
+// via baloo.AddAssertFactory("alias", factory) or at Client level.
+func (e *Expect) AssertWith(name string, args ...interface{}) *Expect {
+	e.AssertFunc(e.assertion(name, args...))
+	return e
+}
+
+// assertion creates the assertion function registered by alias name,
+// falling back to the deprecated global Assertions map.
+func (e *Expect) assertion(name string, args ...interface{}) assert.Func {
+	registry := e.registry()
+	if _, ok := registry.Get(name); !ok {
+		if fn, ok := Assertions[name]; ok {
+			return fn
+		}
+	}
+	return registry.Func(name, args...)
+}
+
+// registry returns the assertion registry
+// inherited from the request Client, if present.
+func (e *Expect) registry() *Registry {
+	if e.request != nil && e.request.Client != nil && e.request.Client.Assertions != nil {
+		return e.request.Client.Assertions
+	}
+	return DefaultRegistry
+}
+
 // AssertFunc adds a new assertion function.
+// If Not() was called before, only the first function is negated.
 func (e *Expect) AssertFunc(assertion ...assert.Func) *Expect {
+	if e.negate && len(assertion) > 0 {
+		e.negate = false
+		// Copy the assertions to not modify the caller slice
+		assertion = append([]assert.Func{assert.Not(assertion[0])}, assertion[1:]...)
+	}
 	e.assertions = append(e.assertions, assertion...)
 	return e
 }
 
+// Not negates the next assertion, which will fail if
+// the assertion succeeds. It applies to any method adding an
+// assertion, such as Status() or AssertFunc(). Binding methods,
+// such as BindJSON(), cannot be negated and reset it.
+func (e *Expect) Not() *Expect {
+	e.negate = true
+	return e
+}
+
+// AnyOf asserts that at least one of the given assertion functions succeeds.
+func (e *Expect) AnyOf(assertions ...assert.Func) *Expect {
+	e.AssertFunc(assert.AnyOf(assertions...))
+	return e
+}
+
+// AllOf asserts that all the given assertion functions succeed.
+func (e *Expect) AllOf(assertions ...assert.Func) *Expect {
+	e.AssertFunc(assert.AllOf(assertions...))
+	return e
+}
+
 // Done performs and asserts the HTTP response based
 // on the defined expectations.
 func (e *Expect) Done() error {
@@ -199,6 +319,7 @@ func (e *Expect) Done() error {
 	if err != nil {
 		err = fmt.Errorf("request error: %s", err)
 		e.test.Error(err)
+		e.report(res, err)
 		return err
 	}
 
@@ -214,6 +335,7 @@ func (e *Expect) Done() error {
 		e.test.Fail()
 	}
 
+	e.report(res, err)
 	return err
 }
 
@@ -223,14 +345,38 @@ func (e *Expect) End() error {
 }
 
 func (e *Expect) run(res *http.Response, req *http.Request) error {
-	var err error
 	for _, assertion := range e.assertions {
-		err = assertion(res, req)
-		if err != nil {
-			break
+		if err := assertion(res, req); err != nil {
+			return err
+		}
+	}
+	// Bind the response body once all the assertions pass
+	for _, bind := range e.binders {
+		if err := bind(res, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// report logs the HTTP exchange dump if verbose mode is enabled
+// or if dump on failure is enabled and the expectation failed.
+func (e *Expect) report(res *gentleman.Response, err error) {
+	opts := envDebugOptions()
+	if e.request.Client != nil {
+		opts = e.request.Client.debugOptions()
+	}
+	if res == nil {
+		return
+	}
+	if opts.verbose || (opts.dump && err != nil) {
+		e.test.Logf("HTTP exchange:\n%s", Dump(res, opts.redact...))
+	}
+	if err != nil {
+		if cmd := reproduceCommand(res.Context, opts.redact); cmd != "" {
+			e.test.Logf("reproduce with:\n%s", cmd)
 		}
 	}
-	return err
 }
 
 // Send does the same as `Done()`, but it also returns the `*http.Response` along with the `error`.
@@ -240,6 +386,7 @@ func (e *Expect) Send() (*gentleman.Response, error) {
 	if err != nil {
 		err = fmt.Errorf("request error: %s", err)
 		e.test.Error(err)
+		e.report(res, err)
 		return res, err
 	}
 
@@ -249,5 +396,6 @@ func (e *Expect) Send() (*gentleman.Response, error) {
 		e.test.Error(err)
 	}
 
+	e.report(res, err)
 	return res, err
 }
@@ -3,11 +3,19 @@ package baloo
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/nbio/st"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/h2non/baloo.v3/assert"
+	_ "gopkg.in/h2non/baloo.v3/codec/cbor"
+	_ "gopkg.in/h2non/baloo.v3/codec/msgpack"
+	_ "gopkg.in/h2non/baloo.v3/codec/protobuf"
+	_ "gopkg.in/h2non/baloo.v3/codec/yaml"
 	"gopkg.in/h2non/gentleman.v2"
 )
 
@@ -112,6 +120,123 @@ func TestExpectHeaderNotPresent(t *testing.T) {
 	st.Expect(t, exp.run(res, nil), nil)
 }
 
+func TestExpectCharset(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		w.Write([]byte("Caf\xe9 cr\xe8me"))
+	}))
+	defer ts.Close()
+
+	New(ts.URL).Get("/").
+		Expect(t).
+		Charset("latin1").
+		BodyEquals("Café crème").
+		BodyMatchString("crème$").
+		Done()
+}
+
+func TestExpectDecoded(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	data := map[string]interface{}{"id": 1, "tags": []string{"foo", "bar"}}
+	for _, contentType := range []string{"application/msgpack", "application/cbor", "application/yaml"} {
+		New(ts.URL).Post("/").
+			Encode(contentType, data).
+			Expect(t).
+			Type(contentType).
+			Decoded(`{"id": 1, "tags": ["foo", "bar"]}`).
+			Done()
+	}
+
+	message := wrapperspb.String("foo")
+	New(ts.URL).Post("/").
+		Encode("application/x-protobuf", message).
+		Expect(t).
+		Decoded(message).
+		Done()
+}
+
+func TestExpectBindJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1, "name": "foo", "role": "admin"}`)
+	}))
+	defer ts.Close()
+
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var out user
+	New(ts.URL).Get("/").
+		Expect(t).
+		BindJSON(&out).
+		Status(200).
+		JSON(`{"id": 1, "name": "foo", "role": "admin"}`).
+		Done()
+	st.Expect(t, out, user{ID: 1, Name: "foo"})
+
+	// Bind is skipped if the assertions fail
+	var skipped user
+	mock := &testingMock{}
+	New(ts.URL).Get("/").
+		Expect(mock).
+		BindJSON(&skipped).
+		Status(404).
+		Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, skipped, user{})
+
+	// Strict mode fails on unknown fields
+	mock = &testingMock{}
+	err := New(ts.URL).Get("/").
+		Expect(mock).
+		BindJSONStrict(&out).
+		Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), `failed to bind JSON body: json: unknown field "role"`)
+}
+
+func TestExpectBindXML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<user><name>foo</name></user>`)
+	}))
+	defer ts.Close()
+
+	var out struct {
+		Name string `xml:"name"`
+	}
+	New(ts.URL).Get("/").
+		Expect(t).
+		Type("xml").
+		BindXML(&out).
+		Done()
+	st.Expect(t, out.Name, "foo")
+}
+
+func TestExpectJSONMatchers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": "f47ac10b-58cc-4372-a567-0e02b2c3d479", "name": "foo", "createdAt": "2024-01-02T03:04:05Z"}`)
+	}))
+	defer ts.Close()
+
+	New(ts.URL).Get("/").
+		Expect(t).
+		JSON(map[string]interface{}{
+			"id":        assert.UUID(),
+			"name":      "foo",
+			"createdAt": assert.RFC3339(),
+		}).
+		Done()
+}
+
 func assertStatus(res *http.Response, req *http.Request) error {
 	if res.StatusCode >= 400 {
 		return errors.New("Invalid server response (> 400)")
@@ -155,3 +280,100 @@ func TestGlobalAssertFunc(t *testing.T) {
 		Assert("foo").
 		Done()
 }
+
+func TestExpectAssertUnknownAlias(t *testing.T) {
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 200}
+	exp := NewExpect(req)
+	exp.Assert("unknown")
+	st.Reject(t, exp.run(res, nil), nil)
+}
+
+func TestExpectAssertMultipleAliases(t *testing.T) {
+	AddAssertFunc("ok", assertStatus)
+	AddAssertFunc("fail", func(res *http.Response, req *http.Request) error {
+		return errors.New("fail")
+	})
+	defer FlushAssertFuncs()
+
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 200}
+	st.Expect(t, NewExpect(req).Assert("ok").run(res, nil), nil)
+	st.Expect(t, NewExpect(req).Assert("ok", "fail").run(res, nil).Error(), "fail")
+}
+
+func TestExpectAssertLegacyMap(t *testing.T) {
+	Assertions["legacy"] = func(res *http.Response, req *http.Request) error {
+		return errors.New("legacy")
+	}
+	defer FlushAssertFuncs()
+
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 200}
+	st.Expect(t, NewExpect(req).Assert("legacy").run(res, nil).Error(), "legacy")
+
+	// Functions stored in the map are resolved from client expectations too
+	exp := NewExpect(New("").Get("/"))
+	st.Expect(t, exp.Assert("legacy").run(res, nil).Error(), "legacy")
+}
+
+func TestGlobalAssertFactory(t *testing.T) {
+	AddAssertFactory("status", assertStatusCode)
+	defer FlushAssertFuncs()
+
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 200}
+	exp := NewExpect(req)
+	exp.AssertWith("status", 200)
+	st.Expect(t, exp.run(res, nil), nil)
+}
+
+func TestExpectNot(t *testing.T) {
+	req := &Request{Request: gentleman.NewRequest()}
+	headers := http.Header{"Foo": []string{"bar"}}
+	res := &http.Response{StatusCode: 200, Header: headers}
+	exp := NewExpect(req)
+	exp.Not().Status(404).Header("foo", "bar")
+	st.Expect(t, exp.run(res, &http.Request{URL: &url.URL{}}), nil)
+
+	exp = NewExpect(req)
+	exp.Not().Status(200)
+	st.Reject(t, exp.run(res, nil), nil)
+}
+
+func TestExpectNotFirstAssertion(t *testing.T) {
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 200}
+	assertions := []assert.Func{assert.StatusEqual(404), assert.StatusEqual(200)}
+
+	exp := NewExpect(req)
+	exp.Not().AssertFunc(assertions...)
+	st.Expect(t, exp.run(res, &http.Request{URL: &url.URL{}}), nil)
+
+	// The caller slice is not modified
+	st.Reject(t, assertions[0](res, &http.Request{URL: &url.URL{}}), nil)
+
+	exp = NewExpect(req)
+	exp.AssertFunc(assertions[1:]...)
+	st.Expect(t, exp.run(res, &http.Request{URL: &url.URL{}}), nil)
+}
+
+func TestExpectNotResetByBinders(t *testing.T) {
+	req := &Request{Request: gentleman.NewRequest()}
+	exp := NewExpect(req)
+	var out map[string]interface{}
+	exp.Not().BindJSON(&out)
+	st.Expect(t, exp.negate, false)
+}
+
+func TestExpectAnyOf(t *testing.T) {
+	req := &Request{Request: gentleman.NewRequest()}
+	res := &http.Response{StatusCode: 204}
+	exp := NewExpect(req)
+	exp.AnyOf(assert.StatusEqual(200), assert.StatusEqual(204))
+	st.Expect(t, exp.run(res, &http.Request{URL: &url.URL{}}), nil)
+
+	exp = NewExpect(req)
+	exp.AllOf(assert.StatusEqual(200), assert.StatusEqual(204))
+	st.Reject(t, exp.run(res, &http.Request{URL: &url.URL{}}), nil)
+}
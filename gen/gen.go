@@ -0,0 +1,233 @@
+// Package gen implements a Go test code generator which translates
+// recorded HTTP exchanges, such as HAR files or Postman collections,
+// into baloo based tests.
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+// Suite represents a set of tests sharing the same client configuration.
+type Suite struct {
+	// Package stores the Go package name of the generated file.
+	Package string
+	// BaseURL stores the client base URL.
+	BaseURL string
+	// Params stores the client level path params.
+	Params map[string]string
+	// Headers stores the client level header fields.
+	Headers map[string]string
+	// Tests stores the suite test cases.
+	Tests []*Test
+}
+
+// Test represents a generated test case, based on a recorded HTTP exchange.
+type Test struct {
+	// Name stores the test case label.
+	Name string
+	// Method stores the HTTP request method.
+	Method string
+	// URL stores the request base URL, if it differs from the suite base URL.
+	URL string
+	// Path stores the request URL path.
+	Path string
+	// Params stores the request path params.
+	Params []Field
+	// Query stores the request URL query params.
+	Query []Field
+	// Headers stores the request header fields.
+	Headers []Field
+	// Body stores the request body.
+	Body string
+	// Status stores the expected response status code.
+	Status int
+	// Type stores the expected response MIME type.
+	Type string
+	// JSON stores the expected response JSON body snapshot.
+	JSON string
+}
+
+// Field represents a name-value pair, such as headers or query params.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// skipHeaders stores the request header fields which are not
+// translated into tests, since they are transport specific.
+var skipHeaders = map[string]bool{
+	"host":              true,
+	"connection":        true,
+	"content-length":    true,
+	"accept-encoding":   true,
+	"cookie":            true,
+	"user-agent":        true,
+	"keep-alive":        true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+}
+
+func skipHeader(name string) bool {
+	name = strings.ToLower(name)
+	return skipHeaders[name] || strings.HasPrefix(name, ":") || strings.HasPrefix(name, "sec-")
+}
+
+// Generate generates the formatted Go test source code of the given suite.
+func Generate(suite *Suite) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	pkg := suite.Package
+	if pkg == "" {
+		pkg = "api"
+	}
+
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+	fmt.Fprintf(buf, "import (\n\t\"testing\"\n\n\t\"gopkg.in/h2non/baloo.v3\"\n)\n\n")
+	fmt.Fprintf(buf, "// test stores the HTTP testing client preconfigured\n")
+	fmt.Fprintf(buf, "var test = baloo.New(%s)", strconv.Quote(suite.BaseURL))
+	for _, key := range httputil.SortedKeys(suite.Params) {
+		fmt.Fprintf(buf, ".\n\tParam(%s, %s)", strconv.Quote(key), strconv.Quote(suite.Params[key]))
+	}
+	for _, key := range httputil.SortedKeys(suite.Headers) {
+		fmt.Fprintf(buf, ".\n\tSetHeader(%s, %s)", strconv.Quote(key), strconv.Quote(suite.Headers[key]))
+	}
+	fmt.Fprintf(buf, "\n")
+
+	names := map[string]int{}
+	for _, test := range suite.Tests {
+		name := funcName(test.Name)
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, names[name])
+		}
+		fmt.Fprintf(buf, "\nfunc %s(t *testing.T) {\n", name)
+		writeTest(buf, test)
+		fmt.Fprintf(buf, "}\n")
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func writeTest(buf *bytes.Buffer, test *Test) {
+	client := "test"
+	if test.URL != "" {
+		client = fmt.Sprintf("baloo.New(%s)", strconv.Quote(test.URL))
+	}
+
+	switch test.Method {
+	case "GET", "POST", "PUT", "DELETE", "PATCH", "HEAD":
+		method := test.Method[:1] + strings.ToLower(test.Method[1:])
+		fmt.Fprintf(buf, "\t%s.%s(%s)", client, method, strconv.Quote(test.Path))
+	default:
+		fmt.Fprintf(buf, "\t%s.Request().\n\t\tMethod(%s).\n\t\tPath(%s)", client, strconv.Quote(test.Method), strconv.Quote(test.Path))
+	}
+
+	for _, field := range test.Params {
+		fmt.Fprintf(buf, ".\n\t\tParam(%s, %s)", strconv.Quote(field.Name), strconv.Quote(field.Value))
+	}
+	for _, field := range test.Query {
+		fmt.Fprintf(buf, ".\n\t\tAddQuery(%s, %s)", strconv.Quote(field.Name), strconv.Quote(field.Value))
+	}
+
+	body := strings.TrimSpace(test.Body)
+	jsonBody := body != "" && json.Valid([]byte(body))
+	for _, field := range test.Headers {
+		if jsonBody && strings.EqualFold(field.Name, "Content-Type") {
+			continue
+		}
+		fmt.Fprintf(buf, ".\n\t\tSetHeader(%s, %s)", strconv.Quote(field.Name), strconv.Quote(field.Value))
+	}
+
+	if jsonBody {
+		fmt.Fprintf(buf, ".\n\t\tJSON(%s)", quote(compactJSON(body)))
+	} else if test.Body != "" {
+		fmt.Fprintf(buf, ".\n\t\tBodyString(%s)", quote(test.Body))
+	}
+
+	fmt.Fprintf(buf, ".\n\t\tExpect(t)")
+	if test.Status != 0 {
+		fmt.Fprintf(buf, ".\n\t\tStatus(%d)", test.Status)
+	} else {
+		fmt.Fprintf(buf, ".\n\t\tStatusOk()")
+	}
+	if test.Type != "" {
+		fmt.Fprintf(buf, ".\n\t\tType(%s)", strconv.Quote(typeAlias(test.Type)))
+	}
+	if test.JSON != "" {
+		fmt.Fprintf(buf, ".\n\t\tJSON(%s)", quote(indentJSON(test.JSON)))
+	}
+	fmt.Fprintf(buf, ".\n\t\tDone()\n")
+}
+
+// typeAlias returns the MIME type alias supported by the Type assertion,
+// or the MIME type quoted as regular expression.
+func typeAlias(mime string) string {
+	mime = strings.TrimSpace(strings.Split(mime, ";")[0])
+	switch {
+	case mime == "application/json":
+		return "json"
+	case mime == "application/xml" || mime == "text/xml":
+		return "xml"
+	case mime == "text/html":
+		return "html"
+	case mime == "text/plain":
+		return "text"
+	}
+	return regexp.QuoteMeta(mime)
+}
+
+// isJSON reports whether the given MIME type is JSON based.
+func isJSON(mime string) bool {
+	mime = strings.ToLower(strings.Split(mime, ";")[0])
+	return strings.HasSuffix(mime, "/json") || strings.HasSuffix(mime, "+json")
+}
+
+func compactJSON(data string) string {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, []byte(data)); err != nil {
+		return data
+	}
+	return buf.String()
+}
+
+func indentJSON(data string) string {
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, []byte(data), "", "  "); err != nil {
+		return data
+	}
+	return buf.String()
+}
+
+// quote returns the given string as Go raw string literal, if possible.
+func quote(s string) string {
+	if strings.Contains(s, "`") || strings.Contains(s, "\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// funcName returns a valid Go test function name based on the given label.
+func funcName(label string) string {
+	buf := &strings.Builder{}
+	buf.WriteString("Test")
+	upper := true
+	for _, r := range label {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestGenerate(t *testing.T) {
+	suite := &Suite{
+		Package: "users",
+		BaseURL: "http://foo.com",
+		Params:  map[string]string{"id": "123"},
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Tests: []*Test{
+			{
+				Name:    "get user",
+				Method:  "GET",
+				Path:    "/users/:id",
+				Query:   []Field{{Name: "foo", Value: "bar"}},
+				Headers: []Field{{Name: "Accept", Value: "application/json"}},
+				Status:  200,
+				Type:    "application/json; charset=utf-8",
+				JSON:    `{"id":123}`,
+			},
+			{
+				Name:    "create user",
+				Method:  "POST",
+				Path:    "/users",
+				Headers: []Field{{Name: "Content-Type", Value: "application/json"}},
+				Body:    `{"name": "foo"}`,
+				Status:  201,
+			},
+			{
+				Name:   "get user",
+				Method: "OPTIONS",
+				URL:    "http://bar.com",
+				Path:   "/users",
+			},
+		},
+	}
+
+	code, err := Generate(suite)
+	st.Expect(t, err, nil)
+
+	src := string(code)
+	st.Expect(t, strings.HasPrefix(src, "package users\n"), true)
+	st.Expect(t, strings.Contains(src, `var test = baloo.New("http://foo.com").
+	Param("id", "123").
+	SetHeader("Authorization", "Bearer token")`), true)
+	st.Expect(t, strings.Contains(src, `func TestGetUser(t *testing.T) {
+	test.Get("/users/:id").
+		AddQuery("foo", "bar").
+		SetHeader("Accept", "application/json").
+		Expect(t).
+		Status(200).
+		Type("json").
+		JSON(`+"`"+`{
+  "id": 123
+}`+"`"+`).
+		Done()
+}`), true)
+	st.Expect(t, strings.Contains(src, `func TestCreateUser(t *testing.T) {
+	test.Post("/users").
+		JSON(`+"`"+`{"name":"foo"}`+"`"+`).
+		Expect(t).
+		Status(201).
+		Done()
+}`), true)
+	st.Expect(t, strings.Contains(src, `func TestGetUser2(t *testing.T) {
+	baloo.New("http://bar.com").Request().
+		Method("OPTIONS").
+		Path("/users").
+		Expect(t).
+		StatusOk().
+		Done()
+}`), true)
+}
+
+func TestTypeAlias(t *testing.T) {
+	st.Expect(t, typeAlias("application/json; charset=utf-8"), "json")
+	st.Expect(t, typeAlias("text/html"), "html")
+	st.Expect(t, typeAlias("application/vnd.api+json"), `application/vnd\.api\+json`)
+}
+
+func TestFuncName(t *testing.T) {
+	st.Expect(t, funcName("GET /users/:id"), "TestGETUsersId")
+	st.Expect(t, funcName("users list"), "TestUsersList")
+}
+
+func TestQuote(t *testing.T) {
+	st.Expect(t, quote("foo"), "`foo`")
+	st.Expect(t, quote("foo`bar"), `"foo`+"`"+`bar"`)
+}
+
+func TestParseUnsupported(t *testing.T) {
+	_, err := Parse([]byte(`{"foo": "bar"}`), nil)
+	st.Reject(t, err, nil)
+	_, err = Parse([]byte(`foo`), nil)
+	st.Reject(t, err, nil)
+}
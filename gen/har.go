@@ -0,0 +1,90 @@
+package gen
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+
+	"gopkg.in/h2non/baloo.v3/har"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+// FromHAR creates a test suite based on the HTTP exchanges
+// recorded in the given HAR document.
+func FromHAR(doc *har.HAR) (*Suite, error) {
+	if doc.Log == nil {
+		return nil, errors.New("gen: invalid HAR document: missing log")
+	}
+
+	suite := &Suite{}
+	hosts := map[string]int{}
+
+	for _, entry := range doc.Log.Entries {
+		if entry.Request == nil {
+			continue
+		}
+
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, err
+		}
+		base := u.Scheme + "://" + u.Host
+		if hosts[base]++; hosts[base] > hosts[suite.BaseURL] || suite.BaseURL == "" {
+			suite.BaseURL = base
+		}
+
+		test := &Test{
+			Name:   entry.Request.Method + " " + u.Path,
+			Method: entry.Request.Method,
+			URL:    base,
+			Path:   u.Path,
+		}
+
+		query := u.Query()
+		for _, key := range httputil.SortedKeys(query) {
+			for _, value := range query[key] {
+				test.Query = append(test.Query, Field{Name: key, Value: value})
+			}
+		}
+
+		for _, header := range entry.Request.Headers {
+			if !skipHeader(header.Name) {
+				test.Headers = append(test.Headers, Field{Name: header.Name, Value: header.Value})
+			}
+		}
+
+		if entry.Request.PostData != nil {
+			test.Body = entry.Request.PostData.Text
+		}
+
+		if res := entry.Response; res != nil {
+			test.Status = res.Status
+			test.Type = res.Content.MimeType
+			if isJSON(res.Content.MimeType) {
+				test.JSON = responseText(res.Content)
+			}
+		}
+
+		suite.Tests = append(suite.Tests, test)
+	}
+
+	// Requests to the suite base URL reuse the client
+	for _, test := range suite.Tests {
+		if test.URL == suite.BaseURL {
+			test.URL = ""
+		}
+	}
+
+	return suite, nil
+}
+
+func responseText(content har.Content) string {
+	if content.Encoding != "base64" {
+		return content.Text
+	}
+	text, err := base64.StdEncoding.DecodeString(content.Text)
+	if err != nil {
+		return ""
+	}
+	return string(text)
+}
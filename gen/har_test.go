@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+const harDocument = `{
+  "log": {
+    "version": "1.2",
+    "creator": {"name": "browser", "version": "1.0"},
+    "entries": [
+      {
+        "request": {
+          "method": "GET",
+          "url": "http://foo.com/users?page=1",
+          "headers": [
+            {"name": "Host", "value": "foo.com"},
+            {"name": ":authority", "value": "foo.com"},
+            {"name": "Accept", "value": "application/json"}
+          ]
+        },
+        "response": {
+          "status": 200,
+          "content": {"mimeType": "application/json", "text": "W3siaWQiOjF9XQ==", "encoding": "base64"}
+        }
+      },
+      {
+        "request": {
+          "method": "POST",
+          "url": "http://foo.com/users",
+          "headers": [{"name": "Content-Type", "value": "application/json"}],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"foo\"}"}
+        },
+        "response": {"status": 201, "content": {"mimeType": "text/plain", "text": "created"}}
+      },
+      {
+        "request": {"method": "GET", "url": "https://bar.com/status"},
+        "response": {"status": 204, "content": {"mimeType": ""}}
+      }
+    ]
+  }
+}`
+
+func TestParseHAR(t *testing.T) {
+	suite, err := Parse([]byte(harDocument), nil)
+	st.Expect(t, err, nil)
+	st.Expect(t, suite.BaseURL, "http://foo.com")
+	st.Expect(t, len(suite.Tests), 3)
+
+	test := suite.Tests[0]
+	st.Expect(t, test.Name, "GET /users")
+	st.Expect(t, test.URL, "")
+	st.Expect(t, test.Path, "/users")
+	st.Expect(t, test.Query, []Field{{Name: "page", Value: "1"}})
+	st.Expect(t, test.Headers, []Field{{Name: "Accept", Value: "application/json"}})
+	st.Expect(t, test.Status, 200)
+	st.Expect(t, test.JSON, `[{"id":1}]`)
+
+	test = suite.Tests[1]
+	st.Expect(t, test.Method, "POST")
+	st.Expect(t, test.Body, `{"name":"foo"}`)
+	st.Expect(t, test.Type, "text/plain")
+	st.Expect(t, test.JSON, "")
+
+	test = suite.Tests[2]
+	st.Expect(t, test.URL, "https://bar.com")
+	st.Expect(t, test.Status, 204)
+
+	_, err = Generate(suite)
+	st.Expect(t, err, nil)
+}
@@ -0,0 +1,44 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+
+	"gopkg.in/h2non/baloo.v3/har"
+)
+
+// Parse creates a test suite based on the given HAR document
+// or Postman collection, which is automatically detected.
+// The optional Postman environment is used to resolve variables.
+func Parse(data []byte, env []byte) (*Suite, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if _, ok := doc["log"]; ok {
+		archive := &har.HAR{}
+		if err := json.Unmarshal(data, archive); err != nil {
+			return nil, err
+		}
+		return FromHAR(archive)
+	}
+
+	if _, ok := doc["item"]; ok {
+		collection := &Collection{}
+		if err := json.Unmarshal(data, collection); err != nil {
+			return nil, err
+		}
+
+		var environment *Environment
+		if len(env) > 0 {
+			environment = &Environment{}
+			if err := json.Unmarshal(env, environment); err != nil {
+				return nil, err
+			}
+		}
+		return FromPostman(collection, environment)
+	}
+
+	return nil, errors.New("gen: unsupported document: expected HAR or Postman v2.1 collection")
+}
@@ -0,0 +1,287 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Collection represents a Postman v2.1 collection.
+type Collection struct {
+	Info     CollectionInfo `json:"info"`
+	Item     []*Item        `json:"item"`
+	Variable []*Variable    `json:"variable"`
+}
+
+// CollectionInfo represents the Postman collection metadata.
+type CollectionInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item represents a Postman collection request item or folder.
+type Item struct {
+	Name     string          `json:"name"`
+	Item     []*Item         `json:"item"`
+	Request  *PostmanRequest `json:"request"`
+	Response []*Example      `json:"response"`
+}
+
+// PostmanRequest represents a Postman collection request.
+type PostmanRequest struct {
+	Method string       `json:"method"`
+	Header []*Variable  `json:"header"`
+	URL    PostmanURL   `json:"url"`
+	Body   *PostmanBody `json:"body"`
+}
+
+// PostmanURL represents a Postman request URL,
+// which can be defined as string or as object.
+type PostmanURL struct {
+	Raw string `json:"raw"`
+	// Variable stores the path variables values, such as ":id".
+	Variable []*Variable `json:"variable"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (u *PostmanURL) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &u.Raw)
+	}
+	type postmanURL PostmanURL
+	return json.Unmarshal(data, (*postmanURL)(u))
+}
+
+// PostmanBody represents a Postman request body.
+type PostmanBody struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw"`
+	URLEncoded []*Variable `json:"urlencoded"`
+}
+
+// Example represents a Postman saved response example.
+type Example struct {
+	Code   int         `json:"code"`
+	Header []*Variable `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// Variable represents a Postman key-value pair, such as
+// variables, environment values and header fields.
+type Variable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+// Environment represents a Postman environment.
+type Environment struct {
+	Name   string      `json:"name"`
+	Values []*Variable `json:"values"`
+}
+
+// active reports whether the variable is enabled.
+func (v *Variable) active() bool {
+	return !v.Disabled && (v.Enabled == nil || *v.Enabled)
+}
+
+var variableRegexp = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// FromPostman creates a test suite based on the given Postman collection
+// and optional environment. Variables used in the URL path are mapped
+// to client level path params, Postman path variables, such as ":id",
+// are mapped to request path params and variables used in header fields
+// are replaced in the request header fields.
+func FromPostman(collection *Collection, env *Environment) (*Suite, error) {
+	if collection.Item == nil {
+		return nil, errors.New("gen: invalid Postman collection: missing items")
+	}
+
+	vars := map[string]string{}
+	for _, v := range collection.Variable {
+		vars[v.Key] = v.Value
+	}
+	if env != nil {
+		for _, v := range env.Values {
+			if v.active() {
+				vars[v.Key] = v.Value
+			}
+		}
+	}
+
+	suite := &Suite{Params: map[string]string{}, Headers: map[string]string{}}
+	hosts := map[string]int{}
+	var walk func(items []*Item, prefix string) error
+
+	walk = func(items []*Item, prefix string) error {
+		for _, item := range items {
+			name := strings.TrimSpace(prefix + " " + item.Name)
+			if item.Request == nil {
+				if err := walk(item.Item, name); err != nil {
+					return err
+				}
+				continue
+			}
+
+			test, err := postmanTest(suite, item, vars)
+			if err != nil {
+				return err
+			}
+			test.Name = name
+			if hosts[test.URL]++; hosts[test.URL] > hosts[suite.BaseURL] || suite.BaseURL == "" {
+				suite.BaseURL = test.URL
+			}
+			suite.Tests = append(suite.Tests, test)
+		}
+		return nil
+	}
+
+	if err := walk(collection.Item, ""); err != nil {
+		return nil, err
+	}
+
+	// Requests to the suite base URL reuse the client
+	for _, test := range suite.Tests {
+		if test.URL == suite.BaseURL {
+			test.URL = ""
+		}
+	}
+
+	return suite, nil
+}
+
+func postmanTest(suite *Suite, item *Item, vars map[string]string) (*Test, error) {
+	req := item.Request
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	base, path, query, err := splitURL(req.URL.Raw, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables in the URL path are mapped to client level path params
+	path = variableRegexp.ReplaceAllStringFunc(path, func(match string) string {
+		name := variableRegexp.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			return match
+		}
+		suite.Params[name] = value
+		return ":" + name
+	})
+
+	test := &Test{Method: method, URL: base, Path: path}
+	for _, v := range req.URL.Variable {
+		if v.Key != "" && strings.Contains(path+"/", "/:"+v.Key+"/") {
+			test.Params = append(test.Params, Field{Name: v.Key, Value: replaceVars(v.Value, vars)})
+		}
+	}
+	for _, field := range query {
+		test.Query = append(test.Query, Field{Name: field.Name, Value: replaceVars(field.Value, vars)})
+	}
+
+	for _, header := range req.Header {
+		if !header.active() || skipHeader(header.Key) {
+			continue
+		}
+		test.Headers = append(test.Headers, Field{Name: header.Key, Value: replaceVars(header.Value, vars)})
+	}
+
+	if body := req.Body; body != nil {
+		switch body.Mode {
+		case "raw":
+			test.Body = replaceVars(body.Raw, vars)
+		case "urlencoded":
+			values := url.Values{}
+			for _, field := range body.URLEncoded {
+				if field.active() {
+					values.Add(field.Key, replaceVars(field.Value, vars))
+				}
+			}
+			test.Body = values.Encode()
+			test.Headers = append(test.Headers, Field{Name: "Content-Type", Value: "application/x-www-form-urlencoded"})
+		}
+	}
+
+	if len(item.Response) > 0 {
+		example := item.Response[0]
+		test.Status = example.Code
+		for _, header := range example.Header {
+			if strings.EqualFold(header.Key, "Content-Type") {
+				test.Type = header.Value
+			}
+		}
+		if isJSON(test.Type) && strings.TrimSpace(example.Body) != "" {
+			test.JSON = example.Body
+		}
+	}
+
+	return test, nil
+}
+
+// splitURL splits the given raw URL into base URL, path and query params,
+// replacing the variables in the base URL and keeping them in the path.
+func splitURL(raw string, vars map[string]string) (string, string, []Field, error) {
+	var rawQuery string
+	if i := strings.Index(raw, "?"); i >= 0 {
+		raw, rawQuery = raw[:i], raw[i+1:]
+	}
+
+	// Variables at the beginning of the URL usually define the base URL
+	if loc := variableRegexp.FindStringSubmatchIndex(raw); loc != nil && loc[0] == 0 {
+		if value, ok := vars[raw[loc[2]:loc[3]]]; ok {
+			raw = value + raw[loc[1]:]
+		}
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	start := strings.Index(raw, "://") + 3
+	base, path := raw, "/"
+	if i := strings.Index(raw[start:], "/"); i >= 0 {
+		base, path = raw[:start+i], raw[start+i:]
+	}
+	base = replaceVars(base, vars)
+	if _, err := url.Parse(base); err != nil {
+		return "", "", nil, err
+	}
+
+	var query []Field
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		field := Field{Name: parts[0]}
+		if len(parts) == 2 {
+			field.Value = parts[1]
+		}
+		if name, err := url.QueryUnescape(field.Name); err == nil {
+			field.Name = name
+		}
+		if value, err := url.QueryUnescape(field.Value); err == nil {
+			field.Value = value
+		}
+		query = append(query, field)
+	}
+
+	return base, path, query, nil
+}
+
+// replaceVars replaces the variables in the given string with their values.
+func replaceVars(s string, vars map[string]string) string {
+	return variableRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		if value, ok := vars[variableRegexp.FindStringSubmatch(match)[1]]; ok {
+			return value
+		}
+		return match
+	})
+}
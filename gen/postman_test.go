@@ -0,0 +1,128 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+const postmanCollection = `{
+  "info": {
+    "name": "Users API",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "variable": [{"key": "baseUrl", "value": "http://localhost:8080"}],
+  "item": [
+    {
+      "name": "users",
+      "item": [
+        {
+          "name": "get user",
+          "request": {
+            "method": "GET",
+            "header": [
+              {"key": "Authorization", "value": "Bearer {{token}}"},
+              {"key": "Accept", "value": "application/json"},
+              {"key": "X-Disabled", "value": "foo", "disabled": true}
+            ],
+            "url": {
+              "raw": "{{baseUrl}}/users/{{userId}}?fields=name&q={{query}}",
+              "host": ["{{baseUrl}}"],
+              "path": ["users", "{{userId}}"]
+            }
+          },
+          "response": [
+            {
+              "name": "ok",
+              "code": 200,
+              "header": [{"key": "Content-Type", "value": "application/json"}],
+              "body": "{\"id\": 123, \"name\": \"foo\"}"
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "name": "create user",
+      "request": {
+        "method": "POST",
+        "url": "{{baseUrl}}/users",
+        "body": {"mode": "raw", "raw": "{\"name\": \"{{name}}\"}"}
+      }
+    },
+    {
+      "name": "delete user",
+      "request": {
+        "method": "DELETE",
+        "header": [{"key": "Authorization", "value": "Bearer {{adminToken}}"}],
+        "url": {
+          "raw": "{{baseUrl}}/users/:id",
+          "path": ["users", ":id"],
+          "variable": [{"key": "id", "value": "{{userId}}"}, {"key": "unused", "value": "foo"}]
+        }
+      }
+    },
+    {
+      "name": "login",
+      "request": {
+        "method": "POST",
+        "url": "https://auth.com/login",
+        "body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "foo"}]}
+      }
+    }
+  ]
+}`
+
+const postmanEnvironment = `{
+  "name": "local",
+  "values": [
+    {"key": "token", "value": "secret", "enabled": true},
+    {"key": "adminToken", "value": "admin", "enabled": true},
+    {"key": "userId", "value": "123", "enabled": true},
+    {"key": "query", "value": "bar", "enabled": true},
+    {"key": "name", "value": "foo", "enabled": false}
+  ]
+}`
+
+func TestParsePostman(t *testing.T) {
+	suite, err := Parse([]byte(postmanCollection), []byte(postmanEnvironment))
+	st.Expect(t, err, nil)
+	st.Expect(t, suite.BaseURL, "http://localhost:8080")
+	st.Expect(t, suite.Params, map[string]string{"userId": "123"})
+	st.Expect(t, suite.Headers, map[string]string{})
+	st.Expect(t, len(suite.Tests), 4)
+
+	test := suite.Tests[0]
+	st.Expect(t, test.Name, "users get user")
+	st.Expect(t, test.URL, "")
+	st.Expect(t, test.Path, "/users/:userId")
+	st.Expect(t, test.Query, []Field{{Name: "fields", Value: "name"}, {Name: "q", Value: "bar"}})
+	st.Expect(t, test.Headers, []Field{{Name: "Authorization", Value: "Bearer secret"}, {Name: "Accept", Value: "application/json"}})
+	st.Expect(t, test.Status, 200)
+	st.Expect(t, test.Type, "application/json")
+	st.Expect(t, test.JSON, `{"id": 123, "name": "foo"}`)
+
+	test = suite.Tests[1]
+	st.Expect(t, test.Method, "POST")
+	st.Expect(t, test.Path, "/users")
+	st.Expect(t, test.Body, `{"name": "{{name}}"}`)
+	st.Expect(t, test.Status, 0)
+
+	// Templated header fields are kept in the request they come from
+	test = suite.Tests[2]
+	st.Expect(t, test.Method, "DELETE")
+	st.Expect(t, test.Path, "/users/:id")
+	st.Expect(t, test.Params, []Field{{Name: "id", Value: "123"}})
+	st.Expect(t, test.Headers, []Field{{Name: "Authorization", Value: "Bearer admin"}})
+
+	test = suite.Tests[3]
+	st.Expect(t, test.URL, "https://auth.com")
+	st.Expect(t, test.Body, "user=foo")
+
+	code, err := Generate(suite)
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(code), `test.Delete("/users/:id").
+		Param("id", "123").
+		SetHeader("Authorization", "Bearer admin")`), true)
+}
@@ -0,0 +1,97 @@
+package baloo
+
+import (
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+// graphQLPayload represents the standard GraphQL over HTTP request body.
+type graphQLPayload struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// GraphQL represents a GraphQL request builder,
+// sending the operation as JSON encoded POST request.
+type GraphQL struct {
+	// Request stores the HTTP request used to send the GraphQL operation.
+	Request *Request
+
+	payload *graphQLPayload
+}
+
+// GraphQL creates a new GraphQL request for the given endpoint path.
+func (c *Client) GraphQL(path string) *GraphQL {
+	payload := &graphQLPayload{}
+	// The payload is serialized once the request is sent
+	req := c.Post(path).JSON(payload)
+	return &GraphQL{Request: req, payload: payload}
+}
+
+// Query defines the GraphQL query or mutation document.
+func (g *GraphQL) Query(doc string) *GraphQL {
+	g.payload.Query = doc
+	return g
+}
+
+// Variables defines the GraphQL operation variables.
+func (g *GraphQL) Variables(variables map[string]interface{}) *GraphQL {
+	for name, value := range variables {
+		g.Variable(name, value)
+	}
+	return g
+}
+
+// Variable defines a GraphQL operation variable by name and value.
+func (g *GraphQL) Variable(name string, value interface{}) *GraphQL {
+	if g.payload.Variables == nil {
+		g.payload.Variables = map[string]interface{}{}
+	}
+	g.payload.Variables[name] = value
+	return g
+}
+
+// OperationName defines the name of the operation to execute,
+// required if the query document contains multiple operations.
+func (g *GraphQL) OperationName(name string) *GraphQL {
+	g.payload.OperationName = name
+	return g
+}
+
+// SetHeader sets a new header field by name and value.
+func (g *GraphQL) SetHeader(name, value string) *GraphQL {
+	g.Request.SetHeader(name, value)
+	return g
+}
+
+// Send executes the GraphQL request and returns the response or error.
+func (g *GraphQL) Send() (*gentleman.Response, error) {
+	return g.Request.Send()
+}
+
+// Expect creates and returns the request test expectation suite.
+func (g *GraphQL) Expect(t TestingT) *Expect {
+	return g.Request.Expect(t)
+}
+
+// GraphQLData asserts the value at the given path of the GraphQL response data,
+// such as "user.friends[0].name". The value is compared as JSON.
+// Strings are compared as JSON strings, use []byte to compare raw JSON documents.
+func (e *Expect) GraphQLData(path string, value interface{}) *Expect {
+	e.AssertFunc(assert.GraphQLData(path, value))
+	return e
+}
+
+// GraphQLNoErrors asserts the GraphQL response has no errors.
+func (e *Expect) GraphQLNoErrors() *Expect {
+	e.AssertFunc(assert.GraphQLNoErrors())
+	return e
+}
+
+// GraphQLError asserts the GraphQL response has an error
+// with the given extensions code or containing the given message.
+func (e *Expect) GraphQLError(match string) *Expect {
+	e.AssertFunc(assert.GraphQLError(match))
+	return e
+}
@@ -0,0 +1,80 @@
+package baloo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func createGraphQLServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query         string                 `json:"query"`
+			Variables     map[string]interface{} `json:"variables"`
+			OperationName string                 `json:"operationName"`
+		}
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(400)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		w.Header().Set("Content-Type", "application/json")
+		if payload.Variables["id"] == "0" {
+			w.Write([]byte(`{"data": {"user": null}, "errors": [{"message": "User not found", "extensions": {"code": "NOT_FOUND"}}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"operation": payload.OperationName,
+				"query":     payload.Query,
+				"user":      map[string]interface{}{"id": payload.Variables["id"], "roles": []string{"admin"}},
+			},
+		})
+	}))
+}
+
+func TestGraphQL(t *testing.T) {
+	ts := createGraphQLServer()
+	defer ts.Close()
+
+	query := `query GetUser($id: ID!) { user(id: $id) { id roles } }`
+	New(ts.URL).GraphQL("/graphql").
+		Query(query).
+		Variables(map[string]interface{}{"id": "1"}).
+		OperationName("GetUser").
+		Expect(t).
+		Status(200).
+		GraphQLNoErrors().
+		GraphQLData("user.id", "1").
+		GraphQLData("user.roles[0]", "admin").
+		GraphQLData("operation", "GetUser").
+		GraphQLData("query", query).
+		Done()
+}
+
+func TestGraphQLError(t *testing.T) {
+	ts := createGraphQLServer()
+	defer ts.Close()
+
+	New(ts.URL).GraphQL("/graphql").
+		Query(`query($id: ID!) { user(id: $id) { id } }`).
+		Variable("id", "0").
+		Expect(t).
+		Status(200).
+		GraphQLError("NOT_FOUND").
+		GraphQLError("User not found").
+		GraphQLData("user", nil).
+		Done()
+
+	mock := &testingMock{}
+	New(ts.URL).GraphQL("/graphql").
+		Variable("id", "0").
+		Expect(mock).
+		GraphQLNoErrors().
+		Done()
+	st.Expect(t, mock.failed, true)
+}
@@ -0,0 +1,119 @@
+package baloo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/h2non/baloo.v3/har"
+)
+
+// HAREnv defines the environment variable used to record every HTTP exchange
+// as HTTP Archive (HAR) 1.2 without test code changes.
+// If the value ends with ".har", all the exchanges are written in a single file,
+// otherwise the value is used as directory where one file per test is written.
+const HAREnv = "BALOO_HAR"
+
+// HARRecorder stores the global HAR recorder used when HAR output is enabled
+// via HAREnv environment variable or SetHAROutput().
+// Recorded entries are removed once written in the HAR output.
+var HARRecorder = newHARRecorder()
+
+var (
+	// harMutex synchronizes HAR files writes.
+	harMutex sync.Mutex
+
+	// harFiles stores the HAR files written by the current process,
+	// where new entries are appended.
+	harFiles = map[string]bool{}
+
+	// harOutputMutex synchronizes the HAR output path access.
+	harOutputMutex sync.RWMutex
+
+	// harOutputPath stores the HAR output defined via SetHAROutput, taking precedence over HAREnv.
+	harOutputPath string
+)
+
+// SetHAROutput enables the HAR recording of every HTTP exchange in the given output path,
+// taking precedence over the HAREnv environment variable.
+// If the path ends with ".har", all the exchanges are written in a single file,
+// otherwise the path is used as directory where one file per test is written.
+// Use an empty path to fall back to the HAREnv environment variable.
+func SetHAROutput(path string) {
+	harOutputMutex.Lock()
+	defer harOutputMutex.Unlock()
+	harOutputPath = path
+}
+
+func newHARRecorder() *har.Recorder {
+	rec := har.NewRecorder()
+	rec.Creator.Version = Version
+	return rec
+}
+
+// harOutput returns the HAR output path, if enabled.
+func harOutput() string {
+	harOutputMutex.RLock()
+	defer harOutputMutex.RUnlock()
+	if harOutputPath != "" {
+		return harOutputPath
+	}
+	return os.Getenv(HAREnv)
+}
+
+// writeHAR writes the pending recorded HAR entries in the HAR output path,
+// removing them from the recorder.
+// Entries are written in a single file or in one file per test,
+// based on the test name stored as entry comment.
+func writeHAR(output string) error {
+	harMutex.Lock()
+	defer harMutex.Unlock()
+
+	entries := HARRecorder.Drain()
+	if len(entries) == 0 {
+		return nil
+	}
+	if strings.HasSuffix(output, ".har") {
+		return appendHAR(output, entries)
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return err
+	}
+
+	var names []string
+	tests := map[string][]*har.Entry{}
+	for _, entry := range entries {
+		name := entry.Comment
+		if name == "" {
+			name = "baloo"
+		}
+		if tests[name] == nil {
+			names = append(names, name)
+		}
+		tests[name] = append(tests[name], entry)
+	}
+	for _, name := range names {
+		if err := appendHAR(filepath.Join(output, har.FileName(name)), tests[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendHAR appends the given entries to the HAR file in the given path,
+// which is overwritten the first time it is written by the current process.
+func appendHAR(path string, entries []*har.Entry) error {
+	if harFiles[path] {
+		err := har.AppendFile(path, entries)
+		if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := har.New(HARRecorder.Creator, entries).WriteFile(path); err != nil {
+		return err
+	}
+	harFiles[path] = true
+	return nil
+}
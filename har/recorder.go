@@ -0,0 +1,373 @@
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// CommentKey defines the context key used to store the
+// recorded entry comment, such as the test name.
+const CommentKey = "$har.comment"
+
+// RedactKey defines the context key used to store additional
+// header fields whose values are redacted in the recorded entry.
+const RedactKey = "$har.redact"
+
+// StreamingTypes stores the response content types streamed by the server,
+// such as Server-Sent Events, whose body is not recorded since
+// reading it would block until the server closes the stream.
+var StreamingTypes = []string{"text/event-stream", "application/x-ndjson"}
+
+const (
+	startKey = "$har.start"
+	bodyKey  = "$har.request.body"
+)
+
+// Recorder implements a gentleman plugin capturing
+// every HTTP exchange as HAR entry.
+type Recorder struct {
+	*plugin.Layer
+
+	// Creator stores the application that creates the HAR log.
+	Creator Creator
+
+	// Redact stores the header fields whose values are redacted in the recorded entries.
+	// Cookie and Set-Cookie also redact the request and response cookie values.
+	Redact []string
+
+	mutex   sync.Mutex
+	entries []*Entry
+}
+
+// NewRecorder creates a new HAR recorder plugin.
+func NewRecorder() *Recorder {
+	r := &Recorder{
+		Layer:   plugin.New(),
+		Creator: Creator{Name: "baloo"},
+		Redact:  append([]string(nil), httputil.RedactedHeaders...),
+	}
+	r.SetHandlers(plugin.Handlers{
+		"before dial": r.beforeDial,
+		"response":    r.response,
+	})
+	return r
+}
+
+// New creates a new HAR document with the given creator and entries.
+func New(creator Creator, entries []*Entry) *HAR {
+	if entries == nil {
+		entries = []*Entry{}
+	}
+	return &HAR{Log: &Log{Version: Version, Creator: &creator, Entries: entries}}
+}
+
+// Write writes the HAR document as JSON in the given writer.
+func (h *HAR) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
+
+// WriteFile writes the HAR document as JSON in the given file path.
+func (h *HAR) WriteFile(path string) error {
+	buf := &bytes.Buffer{}
+	if err := h.Write(buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// entriesEnd and emptyEnd define the end of the documents written by
+// HAR.Write after a non-empty and empty entries list.
+const (
+	entriesEnd = "\n    ]\n  }\n}\n"
+	emptyEnd   = "[]\n  }\n}\n"
+)
+
+// AppendFile appends the given entries to the HAR document written by
+// WriteFile in the given file path, without reading nor encoding
+// the existing entries again.
+func AppendFile(path string, entries []*Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+	end := make([]byte, len(entriesEnd))
+	if size < int64(len(end)) {
+		end = end[:size]
+	}
+	if _, err := file.ReadAt(end, size-int64(len(end))); err != nil {
+		return err
+	}
+
+	// Overwrite the end of the entries list
+	var offset int64
+	sep := ",\n      "
+	switch {
+	case bytes.HasSuffix(end, []byte(entriesEnd)):
+		offset = size - int64(len(entriesEnd))
+	case bytes.HasSuffix(end, []byte(emptyEnd)):
+		offset = size - int64(len(emptyEnd)) + 1
+		sep = "\n      "
+	default:
+		return fmt.Errorf("har: cannot append entries to %s: unexpected document end", path)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, entry := range entries {
+		data, err := json.MarshalIndent(entry, "      ", "  ")
+		if err != nil {
+			return err
+		}
+		buf.WriteString(sep)
+		buf.Write(data)
+		sep = ",\n      "
+	}
+	buf.WriteString(entriesEnd)
+
+	_, err = file.WriteAt(buf.Bytes(), offset)
+	return err
+}
+
+// Entries returns the recorded entries.
+func (r *Recorder) Entries() []*Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*Entry(nil), r.entries...)
+}
+
+// Find returns the recorded entries with the given comment.
+func (r *Recorder) Find(comment string) []*Entry {
+	var entries []*Entry
+	for _, entry := range r.Entries() {
+		if entry.Comment == comment {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// HAR returns the HAR document containing all the recorded entries.
+func (r *Recorder) HAR() *HAR {
+	return New(r.Creator, r.Entries())
+}
+
+// WriteFile writes the HAR document containing all
+// the recorded entries in the given file path.
+func (r *Recorder) WriteFile(path string) error {
+	return r.HAR().WriteFile(path)
+}
+
+// Flush removes the recorded entries.
+func (r *Recorder) Flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = nil
+}
+
+// Drain removes and returns the recorded entries,
+// such as to write them once and release them.
+func (r *Recorder) Drain() []*Entry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entries := r.entries
+	r.entries = nil
+	return entries
+}
+
+func (r *Recorder) beforeDial(ctx *context.Context, h context.Handler) {
+	body, err := httputil.ReadRequestBody(ctx.Request)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	ctx.Set(bodyKey, body)
+	ctx.Set(startKey, time.Now())
+	h.Next(ctx)
+}
+
+func (r *Recorder) response(ctx *context.Context, h context.Handler) {
+	start, ok := ctx.Get(startKey).(time.Time)
+	if !ok {
+		h.Next(ctx)
+		return
+	}
+	wait := time.Since(start)
+
+	var body []byte
+	streaming := IsStreaming(ctx.Response.Header.Get("Content-Type"))
+	if !streaming {
+		var err error
+		if body, err = httputil.ReadResponseBody(ctx.Response); err != nil {
+			h.Error(ctx, err)
+			return
+		}
+	}
+	receive := time.Since(start) - wait
+
+	reqBody, _ := ctx.Get(bodyKey).([]byte)
+	redact, _ := ctx.Get(RedactKey).([]string)
+	redact = append(append([]string(nil), r.Redact...), redact...)
+	entry := &Entry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            millis(wait + receive),
+		Request:         newRequest(ctx.Request, reqBody, redact),
+		Response:        newResponse(ctx.Response, body, redact),
+		Timings:         Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: millis(wait), Receive: millis(receive)},
+		Comment:         ctx.GetString(CommentKey),
+	}
+	if streaming {
+		// The streamed body size is unknown
+		entry.Response.BodySize = -1
+	}
+
+	r.mutex.Lock()
+	r.entries = append(r.entries, entry)
+	r.mutex.Unlock()
+
+	h.Next(ctx)
+}
+
+func newRequest(req *http.Request, body []byte, redact []string) *Request {
+	r := &Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []Cookie{},
+		Headers:     headers(req.Header, redact),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+
+	for _, cookie := range req.Cookies() {
+		value := cookie.Value
+		if httputil.IsRedacted("Cookie", redact) {
+			value = httputil.Redacted
+		}
+		r.Cookies = append(r.Cookies, Cookie{Name: cookie.Name, Value: value})
+	}
+
+	query := req.URL.Query()
+	for _, key := range httputil.SortedKeys(query) {
+		for _, value := range query[key] {
+			r.QueryString = append(r.QueryString, NameValue{Name: key, Value: value})
+		}
+	}
+
+	if len(body) > 0 {
+		r.PostData = &PostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Params:   []NameValue{},
+			Text:     string(body),
+		}
+	}
+
+	return r
+}
+
+func newResponse(res *http.Response, body []byte, redact []string) *Response {
+	r := &Response{
+		Status:      res.StatusCode,
+		StatusText:  http.StatusText(res.StatusCode),
+		HTTPVersion: res.Proto,
+		Cookies:     []Cookie{},
+		Headers:     headers(res.Header, redact),
+		RedirectURL: res.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+		Content: Content{
+			Size:     int64(len(body)),
+			MimeType: res.Header.Get("Content-Type"),
+		},
+	}
+
+	for _, cookie := range res.Cookies() {
+		c := Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			HTTPOnly: cookie.HttpOnly,
+			Secure:   cookie.Secure,
+		}
+		if httputil.IsRedacted("Set-Cookie", redact) {
+			c.Value = httputil.Redacted
+		}
+		if !cookie.Expires.IsZero() {
+			c.Expires = cookie.Expires.Format(time.RFC3339)
+		}
+		r.Cookies = append(r.Cookies, c)
+	}
+
+	if utf8.Valid(body) {
+		r.Content.Text = string(body)
+	} else {
+		r.Content.Text = base64.StdEncoding.EncodeToString(body)
+		r.Content.Encoding = "base64"
+	}
+
+	return r
+}
+
+func headers(header http.Header, redact []string) []NameValue {
+	fields := []NameValue{}
+	for _, key := range httputil.SortedKeys(header) {
+		for _, value := range header[key] {
+			if httputil.IsRedacted(key, redact) {
+				value = httputil.Redacted
+			}
+			fields = append(fields, NameValue{Name: key, Value: value})
+		}
+	}
+	return fields
+}
+
+// IsStreaming reports whether the given Content-Type is one of the StreamingTypes.
+func IsStreaming(contentType string) bool {
+	kind, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, streaming := range StreamingTypes {
+		if strings.EqualFold(kind, streaming) {
+			return true
+		}
+	}
+	return false
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// FileName returns a safe file name for the given test name.
+func FileName(name string) string {
+	return httputil.FileName(name, ".har")
+}
@@ -0,0 +1,164 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+func TestRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(201)
+		fmt.Fprintf(w, "hello %s", body)
+	}))
+	defer ts.Close()
+
+	rec := NewRecorder()
+	rec.Redact = nil
+	cli := gentleman.New()
+	cli.URL(ts.URL)
+	cli.Use(rec)
+
+	req := cli.Request()
+	req.Method("POST")
+	req.Path("/foo")
+	req.SetQuery("bar", "baz")
+	req.AddCookie(&http.Cookie{Name: "foo", Value: "bar"})
+	req.BodyString("world")
+	req.Context.Set(CommentKey, "TestRecorder")
+
+	res, err := req.Send()
+	st.Expect(t, err, nil)
+	st.Expect(t, res.String(), "hello world")
+
+	entries := rec.Entries()
+	st.Expect(t, len(entries), 1)
+	entry := entries[0]
+	st.Expect(t, entry.Comment, "TestRecorder")
+	st.Expect(t, entry.Request.Method, "POST")
+	st.Expect(t, entry.Request.URL, ts.URL+"/foo?bar=baz")
+	st.Expect(t, entry.Request.QueryString, []NameValue{{Name: "bar", Value: "baz"}})
+	st.Expect(t, entry.Request.Cookies, []Cookie{{Name: "foo", Value: "bar"}})
+	st.Expect(t, entry.Request.PostData.Text, "world")
+	st.Expect(t, entry.Request.BodySize, int64(5))
+	st.Expect(t, entry.Response.Status, 201)
+	st.Expect(t, entry.Response.StatusText, "Created")
+	st.Expect(t, entry.Response.Content.Text, "hello world")
+	st.Expect(t, entry.Response.Content.MimeType, "text/plain")
+	st.Expect(t, entry.Response.Cookies[0].Name, "session")
+	st.Expect(t, entry.Response.Cookies[0].HTTPOnly, true)
+
+	st.Expect(t, len(rec.Find("TestRecorder")), 1)
+	st.Expect(t, len(rec.Find("TestOther")), 0)
+
+	rec.Flush()
+	st.Expect(t, len(rec.Entries()), 0)
+}
+
+func TestRecorderBinaryContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte{0xff, 0xfe, 0x00})
+	}))
+	defer ts.Close()
+
+	rec := NewRecorder()
+	req := gentleman.NewRequest()
+	req.URL(ts.URL)
+	req.Use(rec)
+	_, err := req.Send()
+	st.Expect(t, err, nil)
+
+	content := rec.Entries()[0].Response.Content
+	st.Expect(t, content.Encoding, "base64")
+	st.Expect(t, content.Text, "//4A")
+}
+
+func TestHARWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+
+	path := filepath.Join(dir, "test.har")
+	doc := New(Creator{Name: "baloo", Version: "1.0.0"}, nil)
+	st.Expect(t, doc.WriteFile(path), nil)
+
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+
+	var data map[string]interface{}
+	st.Expect(t, json.NewDecoder(bytes.NewReader(buf)).Decode(&data), nil)
+	log := data["log"].(map[string]interface{})
+	st.Expect(t, log["version"], "1.2")
+	st.Expect(t, log["entries"], []interface{}{})
+}
+
+func TestAppendFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	creator := Creator{Name: "baloo"}
+	entries := []*Entry{{Comment: "foo"}, {Comment: "bar"}, {Comment: "baz"}}
+	path := filepath.Join(dir, "test.har")
+	st.Expect(t, New(creator, nil).WriteFile(path), nil)
+	st.Expect(t, AppendFile(path, entries[:1]), nil)
+	st.Expect(t, AppendFile(path, entries[1:]), nil)
+
+	// Appended entries are written as a single HAR document
+	expected := filepath.Join(dir, "expected.har")
+	st.Expect(t, New(creator, entries).WriteFile(expected), nil)
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+	want, err := ioutil.ReadFile(expected)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(buf), string(want))
+
+	invalid := filepath.Join(dir, "invalid.har")
+	st.Expect(t, ioutil.WriteFile(invalid, []byte("{}"), 0644), nil)
+	st.Reject(t, AppendFile(invalid, entries), nil)
+}
+
+func TestFileName(t *testing.T) {
+	st.Expect(t, FileName("TestFoo/bar baz"), "TestFoo_bar_baz.har")
+}
+
+func TestRecorderRedact(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.Header().Set("X-Token", "secret")
+	}))
+	defer ts.Close()
+
+	rec := NewRecorder()
+	cli := gentleman.New()
+	cli.URL(ts.URL)
+	cli.Use(rec)
+
+	req := cli.Request()
+	req.SetHeader("Authorization", "Bearer secret")
+	req.AddCookie(&http.Cookie{Name: "foo", Value: "bar"})
+	req.Context.Set(RedactKey, []string{"X-Token"})
+	_, err := req.Send()
+	st.Expect(t, err, nil)
+
+	entry := rec.Entries()[0]
+	for _, header := range append(entry.Request.Headers, entry.Response.Headers...) {
+		switch header.Name {
+		case "Authorization", "Cookie", "Set-Cookie", "X-Token":
+			st.Expect(t, header.Value, "[REDACTED]")
+		}
+	}
+	st.Expect(t, entry.Request.Cookies, []Cookie{{Name: "foo", Value: "[REDACTED]"}})
+	st.Expect(t, entry.Response.Cookies[0].Value, "[REDACTED]")
+}
@@ -0,0 +1,117 @@
+package baloo
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+func TestHARPerTest(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	os.Setenv(HAREnv, dir)
+	defer os.Unsetenv(HAREnv)
+	defer HARRecorder.Flush()
+
+	New(ts.URL).Get("/foo").Expect(t).Status(200).Done()
+
+	buf, err := ioutil.ReadFile(filepath.Join(dir, "TestHARPerTest.har"))
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(buf), `"comment": "TestHARPerTest"`), true)
+	st.Expect(t, strings.Contains(string(buf), `"url": "`+ts.URL+`/foo"`), true)
+}
+
+func TestHARPerSuite(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "suite.har")
+	os.Setenv(HAREnv, path)
+	defer os.Unsetenv(HAREnv)
+	defer HARRecorder.Flush()
+
+	cli := New(ts.URL)
+	cli.Get("/foo").Expect(t).Status(200).Done()
+	cli.Post("/bar").Expect(t).Status(200).Done()
+
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(buf), `"url": "`+ts.URL+`/foo"`), true)
+	st.Expect(t, strings.Contains(string(buf), `"url": "`+ts.URL+`/bar"`), true)
+}
+
+func TestHAROutputAPI(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "api.har")
+	SetHAROutput(path)
+	defer SetHAROutput("")
+	defer HARRecorder.Flush()
+
+	req := New(ts.URL).Get("/foo").
+		SetHeader("Authorization", "Bearer secret").
+		AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	stack := len(req.Request.Middleware.GetStack())
+	req.Expect(t).Status(200).Done()
+	req.Send()
+	st.Expect(t, len(req.Request.Middleware.GetStack()), stack+1)
+
+	// Written entries are removed from the recorder
+	st.Expect(t, len(HARRecorder.Entries()), 0)
+
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(buf), `"url": "`+ts.URL+`/foo"`), true)
+	st.Expect(t, strings.Contains(string(buf), "secret"), false)
+	st.Expect(t, strings.Contains(string(buf), "abc"), false)
+	st.Expect(t, strings.Contains(string(buf), `"value": "[REDACTED]"`), true)
+}
+
+func TestHARWriteOnError(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "error.har")
+	SetHAROutput(path)
+	defer SetHAROutput("")
+	defer HARRecorder.Flush()
+
+	// Record an exchange pending to be written, such as from a parallel test
+	pending := gentleman.NewRequest().URL(ts.URL + "/foo").Use(HARRecorder)
+	_, err = pending.Send()
+	st.Expect(t, err, nil)
+	st.Expect(t, len(HARRecorder.Entries()), 1)
+
+	ts.Close()
+	_, err = New(ts.URL).Get("/bar").Send()
+	st.Reject(t, err, nil)
+	st.Expect(t, len(HARRecorder.Entries()), 0)
+
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(buf), `"url": "`+ts.URL+`/foo"`), true)
+}
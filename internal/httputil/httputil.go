@@ -0,0 +1,89 @@
+// Package httputil implements HTTP helper functions shared by the baloo packages,
+// such as reading bodies, redacting header fields and naming output files.
+package httputil
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Redacted is the value replacing redacted header and cookie values.
+const Redacted = "[REDACTED]"
+
+// RedactedHeaders stores the header fields whose values are redacted by default.
+var RedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// IsRedacted reports whether the given header field is one of the redacted ones,
+// ignoring case.
+func IsRedacted(key string, redact []string) bool {
+	for _, name := range redact {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadRequestBody reads the request body, re-filling the body stream
+// and defining GetBody to read it again.
+func ReadRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+
+	// Re-fill body reader stream after reading it
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// ReadResponseBody reads the response body, re-filling the body stream.
+func ReadResponseBody(res *http.Response) ([]byte, error) {
+	if res.Body == nil || res.Body == http.NoBody {
+		return []byte{}, nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return []byte{}, err
+	}
+	res.Body.Close()
+
+	// Re-fill body reader stream after reading it
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// FileName returns a safe file name for the given name, such as
+// a test name, replacing the path separators and reserved characters.
+func FileName(name, ext string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name) + ext
+}
+
+// SortedKeys returns the keys of the given map in increasing order.
+func SortedKeys[M ~map[string]V, V any](values M) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
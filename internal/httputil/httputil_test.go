@@ -0,0 +1,54 @@
+package httputil
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestIsRedacted(t *testing.T) {
+	st.Expect(t, IsRedacted("authorization", RedactedHeaders), true)
+	st.Expect(t, IsRedacted("X-Token", RedactedHeaders), false)
+	st.Expect(t, IsRedacted("X-Token", []string{"x-token"}), true)
+}
+
+func TestReadRequestBody(t *testing.T) {
+	req := &http.Request{Body: ioutil.NopCloser(strings.NewReader("hello"))}
+	body, err := ReadRequestBody(req)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(body), "hello")
+
+	body, _ = ioutil.ReadAll(req.Body)
+	st.Expect(t, string(body), "hello")
+	reader, err := req.GetBody()
+	st.Expect(t, err, nil)
+	body, _ = ioutil.ReadAll(reader)
+	st.Expect(t, string(body), "hello")
+
+	body, err = ReadRequestBody(&http.Request{Body: http.NoBody})
+	st.Expect(t, err, nil)
+	st.Expect(t, body == nil, true)
+}
+
+func TestReadResponseBody(t *testing.T) {
+	res := &http.Response{Body: ioutil.NopCloser(strings.NewReader("hello"))}
+	body, err := ReadResponseBody(res)
+	st.Expect(t, err, nil)
+	st.Expect(t, string(body), "hello")
+
+	body, _ = ioutil.ReadAll(res.Body)
+	st.Expect(t, string(body), "hello")
+}
+
+func TestFileName(t *testing.T) {
+	st.Expect(t, FileName("TestUsers/get user: 1", ".har"), "TestUsers_get_user__1.har")
+}
+
+func TestSortedKeys(t *testing.T) {
+	st.Expect(t, SortedKeys(map[string]int{"b": 1, "a": 2}), []string{"a", "b"})
+	st.Expect(t, SortedKeys(url.Values{"foo": nil, "bar": nil}), []string{"bar", "foo"})
+}
@@ -0,0 +1,74 @@
+package baloo
+
+import (
+	"net/http"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+// JSONLines represents the JSON Lines (NDJSON) expectation suite,
+// able to assert the records of a newline-delimited JSON response
+// while streaming, without buffering the whole body in memory.
+type JSONLines struct {
+	expect   *Expect
+	matchers []assert.JSONLinesMatcher
+}
+
+// JSONLines asserts the response body as newline-delimited JSON records,
+// returning the records expectation suite.
+// The response body is consumed by the records assertions.
+func (e *Expect) JSONLines() *JSONLines {
+	lines := &JSONLines{expect: e}
+	e.AssertFunc(func(res *http.Response, req *http.Request) error {
+		return assert.JSONLines(lines.matchers...)(res, req)
+	})
+	return lines
+}
+
+// Count asserts the number of records.
+func (l *JSONLines) Count(count int) *JSONLines {
+	return l.Match(assert.LinesCount(count))
+}
+
+// Schema validates every record against the given JSON schema.
+func (l *JSONLines) Schema(schema string) *JSONLines {
+	return l.Match(assert.LinesSchema(schema))
+}
+
+// At asserts the record at the given zero-based index with the given JSON structure.
+func (l *JSONLines) At(index int, data interface{}) *JSONLines {
+	return l.Match(assert.LineAt(index, data))
+}
+
+// Any asserts at least one record is equal to the given JSON structure.
+func (l *JSONLines) Any(data interface{}) *JSONLines {
+	return l.Match(assert.LineAny(data))
+}
+
+// Match adds custom records matcher functions.
+func (l *JSONLines) Match(matchers ...assert.JSONLinesMatcher) *JSONLines {
+	l.matchers = append(l.matchers, matchers...)
+	return l
+}
+
+// Expect returns the parent expectation suite.
+func (l *JSONLines) Expect() *Expect {
+	return l.expect
+}
+
+// Done performs and asserts the HTTP response based
+// on the defined expectations.
+func (l *JSONLines) Done() error {
+	return l.expect.Done()
+}
+
+// End is an alias to `Done()`.
+func (l *JSONLines) End() error {
+	return l.expect.Done()
+}
+
+// Send does the same as `Done()`, but it also returns the `*http.Response` along with the `error`.
+func (l *JSONLines) Send() (*gentleman.Response, error) {
+	return l.expect.Send()
+}
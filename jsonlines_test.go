@@ -0,0 +1,55 @@
+package baloo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func createJSONLinesServer(count int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		for i := 0; i < count; i++ {
+			encoder.Encode(map[string]interface{}{"id": i, "name": "user"})
+			if i%100 == 0 {
+				w.(http.Flusher).Flush()
+			}
+		}
+	}))
+}
+
+func TestExpectJSONLines(t *testing.T) {
+	ts := createJSONLinesServer(1000)
+	defer ts.Close()
+
+	New(ts.URL).Get("/export").
+		Expect(t).
+		Status(200).
+		Type("ndjson").
+		JSONLines().
+		Count(1000).
+		Schema(`{"type": "object", "required": ["id", "name"]}`).
+		At(0, map[string]interface{}{"id": 0, "name": "user"}).
+		At(999, map[string]interface{}{"id": 999, "name": "user"}).
+		Any(map[string]interface{}{"id": 500, "name": "user"}).
+		Done()
+}
+
+func TestExpectJSONLinesMismatch(t *testing.T) {
+	ts := createJSONLinesServer(10)
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := New(ts.URL).Get("/export").
+		Expect(mock).
+		JSONLines().
+		Any(map[string]interface{}{"id": 10, "name": "user"}).
+		Done()
+
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), `JSON Lines record not found in 10 records: {"id":10,"name":"user"}`)
+}
@@ -0,0 +1,268 @@
+// Package mock implements a local stub HTTP server with a fluent DSL
+// to fake upstream dependencies of the service under test.
+//
+// Example:
+//
+//	srv := mock.NewServer(
+//		mock.Get("/users/:id").MatchHeader("Accept", "json").Reply(200).JSON(user),
+//	)
+//	defer srv.Close()
+//
+//	srv.Post("/users").Reply(201)
+package mock
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// TestingT implements part of the same interface as testing.T
+type TestingT interface {
+	Error(args ...interface{})
+}
+
+// Call represents a request received by the mock server.
+type Call struct {
+	// Request stores the received HTTP request.
+	Request *http.Request
+
+	// Body stores the received request body,
+	// since the request body stream is consumed by the server.
+	Body []byte
+
+	// Params stores the path params of the matched route, if any.
+	Params map[string]string
+
+	// Route stores the matched route, if any.
+	Route *Route
+}
+
+// Mocker is implemented by routes and route responses,
+// so fluent route definitions can be registered as is.
+type Mocker interface {
+	Route() *Route
+}
+
+// Server represents a stub HTTP server replying to the incoming
+// requests based on the registered routes.
+type Server struct {
+	*httptest.Server
+
+	mutex  sync.Mutex
+	routes []*Route
+	calls  []*Call
+}
+
+// NewServer starts and returns a new mock server serving the given routes.
+// The caller should call Close when finished, to shut it down.
+func NewServer(routes ...Mocker) *Server {
+	s := NewUnstartedServer(routes...)
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new mock server serving the given routes
+// but doesn't start it, so the underlying httptest.Server can be configured.
+func NewUnstartedServer(routes ...Mocker) *Server {
+	s := &Server{}
+	s.Server = httptest.NewUnstartedServer(s)
+	s.Add(routes...)
+	return s
+}
+
+// Add registers the given routes in the mock server.
+// It panics if a response is not bound to any route, such as NewResponse().
+func (s *Server) Add(routes ...Mocker) *Server {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, mocker := range routes {
+		var route *Route
+		if mocker != nil {
+			route = mocker.Route()
+		}
+		if route == nil {
+			panic("mock: cannot add a response without route, define it via the route instead, such as Get(path).Reply(200)")
+		}
+		s.routes = append(s.routes, route)
+	}
+	return s
+}
+
+// Route creates and registers a new route for the given method and path.
+func (s *Server) Route(method, path string) *Route {
+	route := NewRoute(method, path)
+	s.Add(route)
+	return route
+}
+
+// Get creates and registers a new GET route for the given path.
+func (s *Server) Get(path string) *Route {
+	return s.Route("GET", path)
+}
+
+// Head creates and registers a new HEAD route for the given path.
+func (s *Server) Head(path string) *Route {
+	return s.Route("HEAD", path)
+}
+
+// Post creates and registers a new POST route for the given path.
+func (s *Server) Post(path string) *Route {
+	return s.Route("POST", path)
+}
+
+// Put creates and registers a new PUT route for the given path.
+func (s *Server) Put(path string) *Route {
+	return s.Route("PUT", path)
+}
+
+// Patch creates and registers a new PATCH route for the given path.
+func (s *Server) Patch(path string) *Route {
+	return s.Route("PATCH", path)
+}
+
+// Delete creates and registers a new DELETE route for the given path.
+func (s *Server) Delete(path string) *Route {
+	return s.Route("DELETE", path)
+}
+
+// Options creates and registers a new OPTIONS route for the given path.
+func (s *Server) Options(path string) *Route {
+	return s.Route("OPTIONS", path)
+}
+
+// Routes returns the registered routes.
+func (s *Server) Routes() []*Route {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*Route(nil), s.routes...)
+}
+
+// Calls returns every request received by the server.
+func (s *Server) Calls() []*Call {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*Call(nil), s.calls...)
+}
+
+// Unmatched returns the received requests not matched by any route.
+func (s *Server) Unmatched() []*Call {
+	var calls []*Call
+	for _, call := range s.Calls() {
+		if call.Route == nil {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes the registered routes and recorded calls.
+func (s *Server) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.routes = nil
+	s.calls = nil
+}
+
+// ServeHTTP replies to the request with the first matching route.
+// Requests not matching any route are replied with 501 Not Implemented.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	call, err := newCall(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	for _, route := range s.routes {
+		if params, ok := route.match(call); ok {
+			call.Route = route
+			call.Params = params
+			route.record(call)
+			break
+		}
+	}
+	s.calls = append(s.calls, call)
+	s.mutex.Unlock()
+
+	if call.Route == nil {
+		msg := fmt.Sprintf("mock: no route matches %s %s", req.Method, req.URL.RequestURI())
+		http.Error(w, msg, http.StatusNotImplemented)
+		return
+	}
+
+	call.Route.serve(w, call)
+}
+
+// AssertCalled reports an error if no request
+// was received for the given method and path.
+// The path can contain route params, such as /users/:id.
+func (s *Server) AssertCalled(t TestingT, method, path string) bool {
+	route := NewRoute(method, path)
+	for _, call := range s.Calls() {
+		if _, ok := route.match(call); ok {
+			return true
+		}
+	}
+	t.Error(fmt.Sprintf("mock: expected call to %s %s, but it was not called", route.method, path))
+	return false
+}
+
+// AssertNotCalled reports an error if any request
+// was received for the given method and path.
+func (s *Server) AssertNotCalled(t TestingT, method, path string) bool {
+	route := NewRoute(method, path)
+	var count int
+	for _, call := range s.Calls() {
+		if _, ok := route.match(call); ok {
+			count++
+		}
+	}
+	if count > 0 {
+		t.Error(fmt.Sprintf("mock: expected no calls to %s %s, but it was called %d times", route.method, path, count))
+		return false
+	}
+	return true
+}
+
+// AssertAllCalled reports an error for every registered route that was not called.
+func (s *Server) AssertAllCalled(t TestingT) bool {
+	ok := true
+	for _, route := range s.Routes() {
+		ok = route.AssertCalled(t) && ok
+	}
+	return ok
+}
+
+// AssertNoUnmatched reports an error if any received request was not matched by a route.
+func (s *Server) AssertNoUnmatched(t TestingT) bool {
+	calls := s.Unmatched()
+	if len(calls) == 0 {
+		return true
+	}
+
+	lines := make([]string, len(calls))
+	for i, call := range calls {
+		lines[i] = fmt.Sprintf("\t- %s %s", call.Request.Method, call.Request.URL.RequestURI())
+	}
+	t.Error(fmt.Sprintf("mock: received %d unmatched requests:\n%s", len(calls), strings.Join(lines, "\n")))
+	return false
+}
+
+// newCall reads the request body and creates a new call.
+func newCall(req *http.Request) (*Call, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return &Call{Request: req, Body: body}, nil
+}
@@ -0,0 +1,134 @@
+package mock
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+type testingMock struct {
+	errors []string
+}
+
+func (t *testingMock) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer(
+		Get("/users/:id").Reply(200).JSON(map[string]string{"name": "foo"}),
+	)
+	defer srv.Close()
+	srv.Post("/users").MatchType("json").Reply(201).BodyString("created")
+
+	test := baloo.New(srv.URL)
+	test.Get("/users/123").
+		Expect(t).
+		Status(200).
+		Type("json").
+		JSON(map[string]string{"name": "foo"}).
+		Done()
+
+	test.Post("/users").
+		JSON(map[string]string{"name": "foo"}).
+		Expect(t).
+		Status(201).
+		BodyEquals("created").
+		Done()
+
+	st.Expect(t, len(srv.Calls()), 2)
+	st.Expect(t, srv.Calls()[0].Params, map[string]string{"id": "123"})
+	st.Expect(t, string(srv.Calls()[1].Body), `{"name":"foo"}`+"\n")
+	st.Expect(t, srv.AssertCalled(t, "GET", "/users/:id"), true)
+	st.Expect(t, srv.AssertAllCalled(t), true)
+	st.Expect(t, srv.AssertNoUnmatched(t), true)
+}
+
+func TestServerUnmatched(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/foo?bar=baz")
+	st.Expect(t, err, nil)
+	body, _ := ioutil.ReadAll(res.Body)
+	st.Expect(t, res.StatusCode, 501)
+	st.Expect(t, string(body), "mock: no route matches GET /foo?bar=baz\n")
+
+	mock := &testingMock{}
+	st.Expect(t, srv.AssertNoUnmatched(mock), false)
+	st.Expect(t, mock.errors, []string{"mock: received 1 unmatched requests:\n\t- GET /foo?bar=baz"})
+	st.Expect(t, srv.AssertCalled(mock, "POST", "/foo"), false)
+	st.Expect(t, mock.errors[1], "mock: expected call to POST /foo, but it was not called")
+	st.Expect(t, srv.AssertNotCalled(mock, "GET", "/foo"), false)
+	st.Expect(t, mock.errors[2], "mock: expected no calls to GET /foo, but it was called 1 times")
+}
+
+func TestServerTimes(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	first := srv.Get("/foo").Times(1)
+	first.Reply(200).BodyString("first")
+	second := srv.Get("/foo")
+	second.Reply(200).BodyString("second")
+
+	test := baloo.New(srv.URL)
+	test.Get("/foo").Expect(t).BodyEquals("first").Done()
+	test.Get("/foo").Expect(t).BodyEquals("second").Done()
+	test.Get("/foo").Expect(t).BodyEquals("second").Done()
+
+	st.Expect(t, first.AssertCalledTimes(t, 1), true)
+	st.Expect(t, second.AssertCalledTimes(t, 2), true)
+}
+
+func TestServerReset(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Get("/foo")
+
+	baloo.New(srv.URL).Get("/foo").Expect(t).Status(200).Done()
+	srv.Reset()
+	st.Expect(t, len(srv.Routes()), 0)
+	st.Expect(t, len(srv.Calls()), 0)
+	baloo.New(srv.URL).Get("/foo").Expect(t).Status(501).Done()
+}
+
+func TestServerAddWithoutRoute(t *testing.T) {
+	defer func() {
+		st.Expect(t, strings.Contains(fmt.Sprint(recover()), "mock: cannot add a response without route"), true)
+	}()
+	NewServer(NewResponse())
+}
+
+func TestServerConcurrentRequests(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	route := srv.Get("/foo")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv.Add(Get("/bar"))
+			baloo.New(srv.URL).Get("/foo").Expect(t).Status(200).Done()
+		}()
+	}
+	wg.Wait()
+	st.Expect(t, route.Called(), 10)
+}
+
+func TestServerReplyFunc(t *testing.T) {
+	srv := NewServer(Delete("/users/:id").ReplyFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	}))
+	defer srv.Close()
+
+	baloo.New(srv.URL).Delete("/users/1").Expect(t).Status(204).Done()
+	st.Expect(t, strings.Contains(srv.Routes()[0].String(), "DELETE /users/:id"), true)
+}
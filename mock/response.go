@@ -0,0 +1,167 @@
+package mock
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// types stores the supported content type aliases.
+var types = map[string]string{
+	"html":       "text/html",
+	"json":       "application/json",
+	"xml":        "application/xml",
+	"text":       "text/plain",
+	"form":       "multipart/form-data",
+	"urlencoded": "application/x-www-form-urlencoded",
+}
+
+// Response represents the mock response replied by a route.
+type Response struct {
+	route   *Route
+	status  int
+	header  http.Header
+	body    []byte
+	delay   time.Duration
+	handler http.HandlerFunc
+}
+
+// NewResponse creates a new 200 OK response.
+func NewResponse() *Response {
+	return &Response{status: http.StatusOK, header: http.Header{}}
+}
+
+// Route returns the route replying with the response, if any,
+// implementing the Mocker interface.
+func (r *Response) Route() *Route {
+	if r == nil {
+		return nil
+	}
+	return r.route
+}
+
+// Status defines the response status code.
+func (r *Response) Status(code int) *Response {
+	r.status = code
+	return r
+}
+
+// SetHeader sets a new header field by name and value.
+// If another header exists with the same key, it will be overwritten.
+func (r *Response) SetHeader(name, value string) *Response {
+	r.header.Set(name, value)
+	return r
+}
+
+// AddHeader adds a new header field by name and value
+// without overwriting any existent header.
+func (r *Response) AddHeader(name, value string) *Response {
+	r.header.Add(name, value)
+	return r
+}
+
+// SetHeaders adds new header fields based on the given map.
+func (r *Response) SetHeaders(fields map[string]string) *Response {
+	for name, value := range fields {
+		r.header.Set(name, value)
+	}
+	return r
+}
+
+// Type defines the Content-Type header field based on the given type name alias or value.
+// You can use the following content type aliases: json, xml, form, html, text and urlencoded.
+func (r *Response) Type(name string) *Response {
+	if value, ok := types[name]; ok {
+		name = value
+	}
+	r.header.Set("Content-Type", name)
+	return r
+}
+
+// Body defines the response body based on a io.Reader stream.
+func (r *Response) Body(reader io.Reader) *Response {
+	body, err := ioutil.ReadAll(reader)
+	if err != nil {
+		panic("mock: cannot read response body: " + err.Error())
+	}
+	r.body = body
+	return r
+}
+
+// BodyString defines the response body based on the given string.
+func (r *Response) BodyString(data string) *Response {
+	r.body = []byte(data)
+	return r
+}
+
+// JSON serializes and defines as response body based on the given input.
+// Strings and bytes are used as raw JSON.
+// The proper Content-Type header will be transparently added for you.
+func (r *Response) JSON(data interface{}) *Response {
+	switch v := data.(type) {
+	case string:
+		r.body = []byte(v)
+	case []byte:
+		r.body = v
+	default:
+		body, err := json.Marshal(data)
+		if err != nil {
+			panic("mock: cannot encode JSON response: " + err.Error())
+		}
+		r.body = body
+	}
+	return r.setDefaultType("application/json")
+}
+
+// XML serializes and defines the response body based on the given input.
+// The proper Content-Type header will be transparently added for you.
+func (r *Response) XML(data interface{}) *Response {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		panic("mock: cannot encode XML response: " + err.Error())
+	}
+	r.body = body
+	return r.setDefaultType("application/xml")
+}
+
+// Delay delays the response by the given duration.
+func (r *Response) Delay(delay time.Duration) *Response {
+	r.delay = delay
+	return r
+}
+
+func (r *Response) setDefaultType(kind string) *Response {
+	if r.header.Get("Content-Type") == "" {
+		r.header.Set("Content-Type", kind)
+	}
+	return r
+}
+
+// write writes the response in the given response writer.
+func (r *Response) write(w http.ResponseWriter, req *http.Request) {
+	if r.delay > 0 {
+		select {
+		case <-time.After(r.delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	if r.handler != nil {
+		r.handler(w, req)
+		return
+	}
+
+	for key, values := range r.header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+	if len(r.body) > 0 {
+		w.Header().Set("Content-Length", strconv.Itoa(len(r.body)))
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body)
+}
@@ -0,0 +1,42 @@
+package mock
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+func TestResponse(t *testing.T) {
+	res := NewResponse().Status(404).SetHeader("Foo", "bar").AddHeader("Foo", "baz").JSON(`{"error":"not found"}`)
+
+	w := httptest.NewRecorder()
+	res.write(w, httptest.NewRequest("GET", "/", nil))
+	st.Expect(t, w.Code, 404)
+	st.Expect(t, w.Header()["Foo"], []string{"bar", "baz"})
+	st.Expect(t, w.Header().Get("Content-Type"), "application/json")
+	st.Expect(t, w.Body.String(), `{"error":"not found"}`)
+}
+
+type user struct {
+	Name string `xml:"name"`
+}
+
+func TestResponseType(t *testing.T) {
+	res := NewResponse().Type("xml").XML(user{"foo"})
+
+	w := httptest.NewRecorder()
+	res.write(w, httptest.NewRequest("GET", "/", nil))
+	st.Expect(t, w.Code, 200)
+	st.Expect(t, w.Header().Get("Content-Type"), "application/xml")
+	st.Expect(t, w.Body.String(), "<user><name>foo</name></user>")
+}
+
+func TestResponseDelay(t *testing.T) {
+	res := NewResponse().Delay(20 * time.Millisecond)
+
+	start := time.Now()
+	res.write(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	st.Expect(t, time.Since(start) >= 20*time.Millisecond, true)
+}
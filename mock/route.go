@@ -0,0 +1,326 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Matcher represents the function used to match incoming requests.
+type Matcher func(call *Call) bool
+
+// Route represents a mock route matching incoming requests
+// by method, path and custom matchers.
+type Route struct {
+	method   string
+	path     string
+	segments []string
+	matchers []Matcher
+	times    int
+	response *Response
+
+	mutex sync.Mutex
+	calls []*Call
+}
+
+// NewRoute creates a new route for the given method and path.
+// The path can contain params, such as /users/:id, or end with a
+// "*" wildcard segment matching any remaining path.
+// An empty method matches any HTTP method.
+func NewRoute(method, path string) *Route {
+	return &Route{
+		method:   strings.ToUpper(method),
+		path:     path,
+		segments: splitPath(path),
+	}
+}
+
+// Get creates a new GET route for the given path.
+func Get(path string) *Route {
+	return NewRoute("GET", path)
+}
+
+// Head creates a new HEAD route for the given path.
+func Head(path string) *Route {
+	return NewRoute("HEAD", path)
+}
+
+// Post creates a new POST route for the given path.
+func Post(path string) *Route {
+	return NewRoute("POST", path)
+}
+
+// Put creates a new PUT route for the given path.
+func Put(path string) *Route {
+	return NewRoute("PUT", path)
+}
+
+// Patch creates a new PATCH route for the given path.
+func Patch(path string) *Route {
+	return NewRoute("PATCH", path)
+}
+
+// Delete creates a new DELETE route for the given path.
+func Delete(path string) *Route {
+	return NewRoute("DELETE", path)
+}
+
+// Options creates a new OPTIONS route for the given path.
+func Options(path string) *Route {
+	return NewRoute("OPTIONS", path)
+}
+
+// Match adds a custom matcher function.
+func (r *Route) Match(fn Matcher) *Route {
+	r.matchers = append(r.matchers, fn)
+	return r
+}
+
+// MatchHeader matches the given header field value against the given regular expression.
+func (r *Route) MatchHeader(key, pattern string) *Route {
+	re := regexp.MustCompile(pattern)
+	return r.Match(func(call *Call) bool {
+		values, ok := call.Request.Header[http.CanonicalHeaderKey(key)]
+		return ok && matchAny(re, values)
+	})
+}
+
+// MatchQuery matches the given URL query param value against the given regular expression.
+func (r *Route) MatchQuery(key, pattern string) *Route {
+	re := regexp.MustCompile(pattern)
+	return r.Match(func(call *Call) bool {
+		values, ok := call.Request.URL.Query()[key]
+		return ok && matchAny(re, values)
+	})
+}
+
+// MatchParam matches the given path param value against the given regular expression.
+func (r *Route) MatchParam(name, pattern string) *Route {
+	re := regexp.MustCompile(pattern)
+	return r.Match(func(call *Call) bool {
+		value, ok := call.Params[name]
+		return ok && re.MatchString(value)
+	})
+}
+
+// MatchType matches the request Content-Type header based on the given type name alias or value.
+// You can use the following content type aliases: json, xml, form, html, text and urlencoded.
+func (r *Route) MatchType(kind string) *Route {
+	if value, ok := types[kind]; ok {
+		kind = value
+	}
+	return r.MatchHeader("Content-Type", regexp.QuoteMeta(kind))
+}
+
+// MatchBody matches the request body against the given regular expression.
+func (r *Route) MatchBody(pattern string) *Route {
+	re := regexp.MustCompile(pattern)
+	return r.Match(func(call *Call) bool {
+		return re.Match(call.Body)
+	})
+}
+
+// MatchJSON matches the request body as JSON, deeply equal to the given value.
+// The value can be a JSON string, bytes or any JSON serializable value.
+func (r *Route) MatchJSON(data interface{}) *Route {
+	expected, err := normalizeJSON(data)
+	if err != nil {
+		panic(fmt.Sprintf("mock: invalid JSON matcher: %s", err))
+	}
+	return r.Match(func(call *Call) bool {
+		var body interface{}
+		if err := json.Unmarshal(call.Body, &body); err != nil {
+			return false
+		}
+		return reflect.DeepEqual(body, expected)
+	})
+}
+
+// Times limits the number of requests the route replies to.
+// Once exhausted, requests are matched by the next routes.
+func (r *Route) Times(n int) *Route {
+	r.times = n
+	return r
+}
+
+// Reply defines the response status code and returns
+// the response to be configured.
+func (r *Route) Reply(status int) *Response {
+	return r.reply().Status(status)
+}
+
+// ReplyFunc defines a custom handler function to reply to the matched requests.
+func (r *Route) ReplyFunc(fn http.HandlerFunc) *Route {
+	r.reply().handler = fn
+	return r
+}
+
+// Route returns the route itself, implementing the Mocker interface.
+func (r *Route) Route() *Route {
+	return r
+}
+
+// reply returns the route response, creating it if needed.
+// Routes without response reply with 200 OK and no body.
+func (r *Route) reply() *Response {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.response == nil {
+		r.response = NewResponse()
+		r.response.route = r
+	}
+	return r.response
+}
+
+// Calls returns the requests matched by the route.
+func (r *Route) Calls() []*Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]*Call(nil), r.calls...)
+}
+
+// Called returns the number of requests matched by the route.
+func (r *Route) Called() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.calls)
+}
+
+// String returns the route method and path.
+func (r *Route) String() string {
+	method := r.method
+	if method == "" {
+		method = "*"
+	}
+	return method + " " + r.path
+}
+
+// AssertCalled reports an error if the route was not called.
+func (r *Route) AssertCalled(t TestingT) bool {
+	if r.Called() == 0 {
+		t.Error(fmt.Sprintf("mock: expected call to %s, but it was not called", r))
+		return false
+	}
+	return true
+}
+
+// AssertCalledTimes reports an error if the route was not called exactly n times.
+func (r *Route) AssertCalledTimes(t TestingT, n int) bool {
+	if count := r.Called(); count != n {
+		t.Error(fmt.Sprintf("mock: expected %d calls to %s, but it was called %d times", n, r, count))
+		return false
+	}
+	return true
+}
+
+// AssertNotCalled reports an error if the route was called.
+func (r *Route) AssertNotCalled(t TestingT) bool {
+	if count := r.Called(); count > 0 {
+		t.Error(fmt.Sprintf("mock: expected no calls to %s, but it was called %d times", r, count))
+		return false
+	}
+	return true
+}
+
+// match returns the path params if the given call matches the route.
+func (r *Route) match(call *Call) (map[string]string, bool) {
+	if r.method != "" && r.method != call.Request.Method {
+		return nil, false
+	}
+
+	params, ok := matchPath(r.segments, splitPath(call.Request.URL.Path))
+	if !ok {
+		return nil, false
+	}
+
+	r.mutex.Lock()
+	exhausted := r.times > 0 && len(r.calls) >= r.times
+	r.mutex.Unlock()
+	if exhausted {
+		return nil, false
+	}
+
+	// Matchers can access the path params
+	prev := call.Params
+	call.Params = params
+	defer func() { call.Params = prev }()
+
+	for _, matcher := range r.matchers {
+		if !matcher(call) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// record records the given call as matched by the route.
+func (r *Route) record(call *Call) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// serve writes the route response.
+func (r *Route) serve(w http.ResponseWriter, call *Call) {
+	r.reply().write(w, call.Request)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func matchPath(pattern, segments []string) (map[string]string, bool) {
+	params := map[string]string{}
+	for i, segment := range pattern {
+		if segment == "*" && i == len(pattern)-1 {
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(segment, ":") {
+			params[segment[1:]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, len(pattern) == len(segments)
+}
+
+func matchAny(re *regexp.Regexp, values []string) bool {
+	for _, value := range values {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeJSON(data interface{}) (interface{}, error) {
+	var buf []byte
+	switch v := data.(type) {
+	case string:
+		buf = []byte(v)
+	case []byte:
+		buf = v
+	default:
+		var err error
+		if buf, err = json.Marshal(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var value interface{}
+	err := json.Unmarshal(buf, &value)
+	return value, err
+}
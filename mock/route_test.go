@@ -0,0 +1,93 @@
+package mock
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func newTestCall(method, url, body string) *Call {
+	req := httptest.NewRequest(method, url, bytes.NewBufferString(body))
+	call, _ := newCall(req)
+	return call
+}
+
+func TestRouteMatchPath(t *testing.T) {
+	cases := []struct {
+		path  string
+		url   string
+		match bool
+	}{
+		{"/", "/", true},
+		{"/foo", "/foo/", true},
+		{"/foo", "/bar", false},
+		{"/foo", "/foo/bar", false},
+		{"/users/:id", "/users/123", true},
+		{"/users/:id", "/users", false},
+		{"/static/*", "/static/js/app.js", true},
+		{"/static/*", "/static", true},
+		{"*", "/foo/bar", true},
+	}
+
+	for _, c := range cases {
+		_, ok := Get(c.path).match(newTestCall("GET", c.url, ""))
+		st.Expect(t, ok, c.match)
+	}
+}
+
+func TestRouteMatchMethod(t *testing.T) {
+	_, ok := Post("/foo").match(newTestCall("GET", "/foo", ""))
+	st.Expect(t, ok, false)
+	_, ok = NewRoute("", "/foo").match(newTestCall("PUT", "/foo", ""))
+	st.Expect(t, ok, true)
+}
+
+func TestRouteMatchParams(t *testing.T) {
+	params, ok := Get("/users/:id/posts/:post").MatchParam("id", `^\d+$`).
+		match(newTestCall("GET", "/users/123/posts/foo", ""))
+	st.Expect(t, ok, true)
+	st.Expect(t, params, map[string]string{"id": "123", "post": "foo"})
+
+	_, ok = Get("/users/:id").MatchParam("id", `^\d+$`).match(newTestCall("GET", "/users/foo", ""))
+	st.Expect(t, ok, false)
+}
+
+func TestRouteMatchers(t *testing.T) {
+	call := newTestCall("POST", "/foo?page=2", `{"name": "foo", "age": 20}`)
+	call.Request.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	matches := func(route *Route) bool {
+		_, ok := route.match(call)
+		return ok
+	}
+
+	st.Expect(t, matches(Post("/foo").MatchHeader("Content-Type", "json")), true)
+	st.Expect(t, matches(Post("/foo").MatchHeader("Accept", ".*")), false)
+	st.Expect(t, matches(Post("/foo").MatchType("json")), true)
+	st.Expect(t, matches(Post("/foo").MatchType("xml")), false)
+	st.Expect(t, matches(Post("/foo").MatchQuery("page", `^2$`)), true)
+	st.Expect(t, matches(Post("/foo").MatchQuery("page", `^1$`)), false)
+	st.Expect(t, matches(Post("/foo").MatchBody(`"name":\s*"foo"`)), true)
+	st.Expect(t, matches(Post("/foo").MatchJSON(map[string]interface{}{"name": "foo", "age": 20})), true)
+	st.Expect(t, matches(Post("/foo").MatchJSON(`{"age":20,"name":"foo"}`)), true)
+	st.Expect(t, matches(Post("/foo").MatchJSON(`{"name":"bar"}`)), false)
+	st.Expect(t, matches(Post("/foo").Match(func(c *Call) bool {
+		return c.Request.Method == http.MethodPost
+	})), true)
+}
+
+func TestRouteAssertions(t *testing.T) {
+	route := Get("/foo")
+	mock := &testingMock{}
+	st.Expect(t, route.AssertCalled(mock), false)
+	st.Expect(t, route.AssertNotCalled(mock), true)
+	st.Expect(t, mock.errors, []string{"mock: expected call to GET /foo, but it was not called"})
+
+	route.record(newTestCall("GET", "/foo", ""))
+	st.Expect(t, route.AssertCalled(mock), true)
+	st.Expect(t, route.AssertCalledTimes(mock, 2), false)
+	st.Expect(t, mock.errors[1], "mock: expected 2 calls to GET /foo, but it was called 1 times")
+}
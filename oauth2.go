@@ -0,0 +1,294 @@
+package baloo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// OAuth2 grant types supported by the OAuth2 plugin.
+const (
+	// GrantClientCredentials uses the client credentials grant.
+	GrantClientCredentials = "client_credentials"
+	// GrantPassword uses the resource owner password credentials grant.
+	GrantPassword = "password"
+	// GrantRefreshToken uses the refresh token grant.
+	GrantRefreshToken = "refresh_token"
+)
+
+// OAuth2Config represents the OAuth2 token endpoint configuration
+// used to obtain access tokens.
+type OAuth2Config struct {
+	// TokenURL stores the token endpoint URL.
+	TokenURL string
+	// GrantType stores the grant type. Defaults to GrantClientCredentials.
+	GrantType string
+	// ClientID stores the client identifier.
+	ClientID string
+	// ClientSecret stores the client secret.
+	ClientSecret string
+	// Scopes stores the requested scopes.
+	Scopes []string
+	// Username stores the resource owner username used by GrantPassword.
+	Username string
+	// Password stores the resource owner password used by GrantPassword.
+	Password string
+	// RefreshToken stores the refresh token used by GrantRefreshToken.
+	RefreshToken string
+	// AuthInParams sends the client credentials as form params
+	// instead of using HTTP Basic authentication.
+	AuthInParams bool
+	// ExpiryDelta defines how long before expiring tokens are refreshed.
+	// Defaults to 10 seconds.
+	ExpiryDelta time.Duration
+	// HTTPClient stores the HTTP client used to request tokens.
+	// Defaults to the client used by the request being authorized.
+	HTTPClient *http.Client
+}
+
+// OAuth2Token represents an OAuth2 access token.
+type OAuth2Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+// expired reports whether the token is expired or about to expire.
+func (t *OAuth2Token) expired(delta time.Duration) bool {
+	return !t.Expiry.IsZero() && time.Now().Add(delta).After(t.Expiry)
+}
+
+// OAuth2 authorizes the client requests with an OAuth2 access token,
+// obtained from the token endpoint based on the given configuration.
+// The token is cached, shared with child clients via UseParent, and refreshed
+// on expiry or when the server replies with 401 Unauthorized.
+func (c *Client) OAuth2(config OAuth2Config) *Client {
+	c.oauth2 = newOAuth2Auth(config)
+	return c.Use(c.oauth2)
+}
+
+// OAuth2Token returns the current OAuth2 access token of the client
+// or its parent clients, fetching it if needed.
+func (c *Client) OAuth2Token() (*OAuth2Token, error) {
+	for cli := c; cli != nil; cli = cli.Parent {
+		if cli.oauth2 != nil {
+			return cli.oauth2.token(nil, false)
+		}
+	}
+	return nil, errors.New("oauth2: client not configured")
+}
+
+// oauth2Auth implements the OAuth2 plugin.
+type oauth2Auth struct {
+	*plugin.Layer
+
+	config OAuth2Config
+
+	mutex  sync.Mutex
+	cached *OAuth2Token
+}
+
+func newOAuth2Auth(config OAuth2Config) *oauth2Auth {
+	if config.GrantType == "" {
+		config.GrantType = GrantClientCredentials
+	}
+	if config.ExpiryDelta == 0 {
+		config.ExpiryDelta = 10 * time.Second
+	}
+	o := &oauth2Auth{Layer: plugin.New(), config: config}
+	o.SetHandlers(plugin.Handlers{
+		"before dial": o.beforeDial,
+		"response":    o.response,
+	})
+	return o
+}
+
+// beforeDial authorizes the request with the cached token, fetching it if needed.
+func (o *oauth2Auth) beforeDial(ctx *context.Context, h context.Handler) {
+	if retrying(ctx) {
+		h.Next(ctx)
+		return
+	}
+	token, err := o.token(ctx.Client, false)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	authorizeToken(ctx.Request, token)
+	h.Next(ctx)
+}
+
+// response retries the request with a new token once the server replies with 401.
+func (o *oauth2Auth) response(ctx *context.Context, h context.Handler) {
+	if ctx.Response.StatusCode != http.StatusUnauthorized {
+		h.Next(ctx)
+		return
+	}
+
+	sent := ctx.Request.Header.Get("Authorization")
+	err := retryRequest(ctx, func(req *http.Request) error {
+		token, err := o.token(ctx.Client, true, sent)
+		if err != nil {
+			return err
+		}
+		authorizeToken(req, token)
+		return nil
+	})
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	h.Next(ctx)
+}
+
+// token returns the cached token, fetching a new one if expired or forced.
+// A forced refresh is skipped if the cached token was already renewed
+// after being sent as the given rejected authorization, by a concurrent request.
+func (o *oauth2Auth) token(client *http.Client, force bool, rejected ...string) (*OAuth2Token, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if token := o.cached; token != nil {
+		renewed := len(rejected) > 0 && authorization(token) != rejected[0]
+		if (!force || renewed) && !token.expired(o.config.ExpiryDelta) {
+			return token, nil
+		}
+	}
+
+	if o.config.HTTPClient != nil || client == nil {
+		client = o.config.HTTPClient
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	refresh := o.config.RefreshToken
+	if o.cached != nil && o.cached.RefreshToken != "" {
+		refresh = o.cached.RefreshToken
+	}
+
+	var token *OAuth2Token
+	var err error
+	if refresh != "" && (o.cached != nil || o.config.GrantType == GrantRefreshToken) {
+		params := url.Values{"grant_type": {GrantRefreshToken}, "refresh_token": {refresh}}
+		if token, err = o.fetch(client, params); err == nil && token.RefreshToken == "" {
+			token.RefreshToken = refresh
+		}
+	}
+
+	// Fallback to the configured grant if the token cannot be refreshed
+	if token == nil && o.config.GrantType != GrantRefreshToken {
+		params := url.Values{"grant_type": {o.config.GrantType}}
+		switch o.config.GrantType {
+		case GrantPassword:
+			params.Set("username", o.config.Username)
+			params.Set("password", o.config.Password)
+		case GrantClientCredentials:
+		default:
+			return nil, fmt.Errorf("oauth2: unsupported grant type: %s", o.config.GrantType)
+		}
+		token, err = o.fetch(client, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errors.New("oauth2: missing refresh token")
+	}
+
+	o.cached = token
+	return token, nil
+}
+
+// fetch requests a new token to the token endpoint with the given form params.
+func (o *oauth2Auth) fetch(client *http.Client, params url.Values) (*OAuth2Token, error) {
+	if len(o.config.Scopes) > 0 {
+		params.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	if o.config.AuthInParams {
+		params.Set("client_id", o.config.ClientID)
+		if o.config.ClientSecret != "" {
+			params.Set("client_secret", o.config.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequest("POST", o.config.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !o.config.AuthInParams && o.config.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: cannot fetch token: %s", err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: cannot fetch token: %s", err)
+	}
+
+	var data struct {
+		AccessToken      string      `json:"access_token"`
+		TokenType        string      `json:"token_type"`
+		RefreshToken     string      `json:"refresh_token"`
+		ExpiresIn        json.Number `json:"expires_in"`
+		Error            string      `json:"error"`
+		ErrorDescription string      `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil && res.StatusCode < 300 {
+		return nil, fmt.Errorf("oauth2: cannot parse token response: %s", err)
+	}
+
+	if res.StatusCode >= 300 || data.Error != "" {
+		msg := fmt.Sprintf("oauth2: cannot fetch token: %s", res.Status)
+		if data.Error != "" {
+			msg += ": " + data.Error
+		}
+		if data.ErrorDescription != "" {
+			msg += ": " + data.ErrorDescription
+		}
+		return nil, errors.New(msg)
+	}
+	if data.AccessToken == "" {
+		return nil, errors.New("oauth2: server response missing access_token")
+	}
+
+	token := &OAuth2Token{
+		AccessToken:  data.AccessToken,
+		TokenType:    data.TokenType,
+		RefreshToken: data.RefreshToken,
+	}
+	if expires, err := data.ExpiresIn.Int64(); err == nil && expires > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expires) * time.Second)
+	}
+	return token, nil
+}
+
+// authorization returns the Authorization header value of the given token.
+func authorization(token *OAuth2Token) string {
+	kind := token.TokenType
+	if kind == "" || strings.EqualFold(kind, "bearer") {
+		kind = "Bearer"
+	}
+	return kind + " " + token.AccessToken
+}
+
+func authorizeToken(req *http.Request, token *OAuth2Token) {
+	req.Header.Set("Authorization", authorization(token))
+}
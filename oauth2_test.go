@@ -0,0 +1,156 @@
+package baloo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+// tokenServer implements a stub OAuth2 token endpoint and protected API.
+type tokenServer struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	grants    []string
+	valid     string
+	expiresIn int
+	count     int
+}
+
+func createTokenServer(expiresIn int) *tokenServer {
+	s := &tokenServer{expiresIn: expiresIn}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if r.URL.Path != "/token" {
+			if r.Header.Get("Authorization") != "Bearer "+s.valid {
+				w.WriteHeader(401)
+				return
+			}
+			fmt.Fprint(w, "ok")
+			return
+		}
+
+		r.ParseForm()
+		grant := r.PostForm.Get("grant_type")
+		s.grants = append(s.grants, grant)
+
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			user, pass = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		invalidRefresh := grant == GrantRefreshToken && !strings.HasPrefix(r.PostForm.Get("refresh_token"), "refresh-")
+		invalidUser := grant == GrantPassword && r.PostForm.Get("password") != "pass"
+		if user != "client" || pass != "secret" || invalidRefresh || invalidUser {
+			w.WriteHeader(400)
+			fmt.Fprint(w, `{"error":"invalid_grant","error_description":"invalid credentials"}`)
+			return
+		}
+
+		s.count++
+		s.valid = fmt.Sprintf("token-%d", s.count)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  s.valid,
+			"token_type":    "bearer",
+			"expires_in":    s.expiresIn,
+			"refresh_token": fmt.Sprintf("refresh-%d", s.count),
+			"scope":         r.PostForm.Get("scope"),
+		})
+	}))
+	return s
+}
+
+func (s *tokenServer) revoke() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.valid = "revoked"
+}
+
+func (s *tokenServer) usedGrants() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.grants...)
+}
+
+func TestClientOAuth2(t *testing.T) {
+	ts := createTokenServer(3600)
+	defer ts.Close()
+
+	parent := New(ts.URL).OAuth2(OAuth2Config{
+		TokenURL:     ts.URL + "/token",
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	})
+	parent.Get("/api").Expect(t).Status(200).BodyEquals("ok").Done()
+	parent.Get("/api").Expect(t).Status(200).Done()
+
+	// Child clients share the parent token
+	child := New(ts.URL).UseParent(parent)
+	child.Get("/api").Expect(t).Status(200).Done()
+
+	token, err := child.OAuth2Token()
+	st.Expect(t, err, nil)
+	st.Expect(t, token.AccessToken, "token-1")
+	st.Expect(t, token.RefreshToken, "refresh-1")
+	st.Expect(t, ts.usedGrants(), []string{GrantClientCredentials})
+
+	// Tokens are refreshed once rejected by the server
+	ts.revoke()
+	child.Get("/api").Expect(t).Status(200).Done()
+	st.Expect(t, ts.usedGrants(), []string{GrantClientCredentials, GrantRefreshToken})
+
+	token, _ = parent.OAuth2Token()
+	st.Expect(t, token.AccessToken, "token-2")
+}
+
+func TestClientOAuth2Expiry(t *testing.T) {
+	ts := createTokenServer(5)
+	defer ts.Close()
+
+	cli := New(ts.URL).OAuth2(OAuth2Config{
+		TokenURL:     ts.URL + "/token",
+		GrantType:    GrantPassword,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Username:     "user",
+		Password:     "pass",
+		AuthInParams: true,
+	})
+	// Tokens expiring within ExpiryDelta are refreshed
+	cli.Get("/api").Expect(t).Status(200).Done()
+	cli.Get("/api").Expect(t).Status(200).Done()
+	st.Expect(t, ts.usedGrants(), []string{GrantPassword, GrantRefreshToken})
+}
+
+func TestClientOAuth2RefreshToken(t *testing.T) {
+	ts := createTokenServer(0)
+	defer ts.Close()
+
+	config := OAuth2Config{
+		TokenURL:     ts.URL + "/token",
+		GrantType:    GrantRefreshToken,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RefreshToken: "refresh-0",
+	}
+	New(ts.URL).OAuth2(config).Get("/api").Expect(t).Status(200).Done()
+	st.Expect(t, ts.usedGrants(), []string{GrantRefreshToken})
+
+	config.RefreshToken = "invalid"
+	_, err := New(ts.URL).OAuth2(config).Get("/api").Send()
+	st.Expect(t, err.Error(), "oauth2: cannot fetch token: 400 Bad Request: invalid_grant: invalid credentials")
+}
+
+func TestClientOAuth2NotConfigured(t *testing.T) {
+	_, err := New("http://foo.com").OAuth2Token()
+	st.Expect(t, err.Error(), "oauth2: client not configured")
+}
@@ -0,0 +1,308 @@
+package pact
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MatchBody compares the actual JSON body against the expected one based on
+// the given body matching rules, returning the found mismatches.
+// Values without rules must be equal, while actual objects may contain
+// additional fields.
+func MatchBody(expected, actual []byte, rules map[string]*RuleSet) []string {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	var exp, act interface{}
+	if err := json.Unmarshal(expected, &exp); err != nil {
+		return []string{fmt.Sprintf("$: invalid expected JSON body: %s", err)}
+	}
+	if err := json.Unmarshal(actual, &act); err != nil {
+		return []string{fmt.Sprintf("$: invalid JSON body: %s", err)}
+	}
+
+	m := newMatcher(rules)
+	m.compare([]string{"$"}, exp, act, false)
+	return m.errors
+}
+
+// MatchHeader matches the actual header value against the
+// expected one based on the given header matching rules.
+func MatchHeader(name, expected, actual string, rules map[string]*RuleSet) error {
+	for key, set := range rules {
+		if strings.EqualFold(key, name) {
+			m := &matcher{}
+			m.apply(name, set, expected, actual)
+			if len(m.errors) > 0 {
+				return fmt.Errorf("%s", m.errors[0])
+			}
+			return nil
+		}
+	}
+	if expected != actual {
+		return fmt.Errorf("%s: expected %q, got %q", name, expected, actual)
+	}
+	return nil
+}
+
+type pathRule struct {
+	tokens []string
+	set    *RuleSet
+}
+
+type matcher struct {
+	rules  []pathRule
+	errors []string
+}
+
+func newMatcher(rules map[string]*RuleSet) *matcher {
+	m := &matcher{}
+	for path, set := range rules {
+		m.rules = append(m.rules, pathRule{tokens: parsePath(path), set: set})
+	}
+	return m
+}
+
+// lookup returns the most specific rule set matching the given path.
+func (m *matcher) lookup(tokens []string) *RuleSet {
+	var found *RuleSet
+	best := -1
+	for _, rule := range m.rules {
+		if len(rule.tokens) != len(tokens) {
+			continue
+		}
+		score := 0
+		for i, token := range rule.tokens {
+			if token == "*" {
+				continue
+			}
+			if token != tokens[i] {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > best {
+			best, found = score, rule.set
+		}
+	}
+	return found
+}
+
+func (m *matcher) fail(tokens []string, format string, args ...interface{}) {
+	m.errors = append(m.errors, formatPath(tokens)+": "+fmt.Sprintf(format, args...))
+}
+
+func (m *matcher) compare(tokens []string, expected, actual interface{}, byType bool) {
+	if set := m.lookup(tokens); set != nil {
+		if !m.apply(formatPath(tokens), set, expected, actual) {
+			return
+		}
+		for _, rule := range set.Matchers {
+			switch rule.Match {
+			case "type":
+				byType = true
+			case "regex", "equality", "integer", "decimal", "number":
+				return
+			}
+		}
+	} else if byType {
+		if kind(expected) != kind(actual) {
+			m.fail(tokens, "expected %s, got %s", kind(expected), kind(actual))
+			return
+		}
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			m.fail(tokens, "expected object, got %s", kind(actual))
+			return
+		}
+		for key, value := range exp {
+			child := append(tokens[:len(tokens):len(tokens)], key)
+			actualValue, ok := act[key]
+			if !ok {
+				m.fail(child, "missing field")
+				continue
+			}
+			m.compare(child, value, actualValue, byType)
+		}
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			m.fail(tokens, "expected array, got %s", kind(actual))
+			return
+		}
+		if !byType && len(exp) != len(act) {
+			m.fail(tokens, "expected array of length %d, got %d", len(exp), len(act))
+			return
+		}
+		for i, value := range act {
+			child := append(tokens[:len(tokens):len(tokens)], strconv.Itoa(i))
+			if byType {
+				// Every element must match the first expected element
+				if len(exp) > 0 {
+					m.compare(child, exp[0], value, byType)
+				}
+				continue
+			}
+			m.compare(child, exp[i], value, byType)
+		}
+	default:
+		if byType {
+			return
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			m.fail(tokens, "expected %s, got %s", encode(expected), encode(actual))
+		}
+	}
+}
+
+// apply applies the given rule set, returning true if matched.
+func (m *matcher) apply(path string, set *RuleSet, expected, actual interface{}) bool {
+	var errors []string
+	for _, rule := range set.Matchers {
+		if err := applyRule(rule, expected, actual); err != "" {
+			errors = append(errors, err)
+		}
+	}
+
+	matched := len(errors) == 0
+	if set.Combine == "OR" {
+		matched = len(errors) < len(set.Matchers)
+	}
+	if !matched {
+		m.errors = append(m.errors, path+": "+strings.Join(errors, ", "))
+	}
+	return matched
+}
+
+func applyRule(rule *Rule, expected, actual interface{}) string {
+	switch rule.Match {
+	case "type":
+		if kind(expected) != kind(actual) {
+			return fmt.Sprintf("expected %s, got %s", kind(expected), kind(actual))
+		}
+		if values, ok := actual.([]interface{}); ok {
+			if rule.Min != nil && len(values) < *rule.Min {
+				return fmt.Sprintf("expected at least %d elements, got %d", *rule.Min, len(values))
+			}
+			if rule.Max != nil && len(values) > *rule.Max {
+				return fmt.Sprintf("expected at most %d elements, got %d", *rule.Max, len(values))
+			}
+		}
+	case "regex":
+		value, ok := actual.(string)
+		if !ok {
+			return fmt.Sprintf("expected string matching %q, got %s", rule.Regex, kind(actual))
+		}
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return fmt.Sprintf("invalid regex %q: %s", rule.Regex, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Sprintf("expected %q to match %q", value, rule.Regex)
+		}
+	case "equality":
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Sprintf("expected %s, got %s", encode(expected), encode(actual))
+		}
+	case "integer", "decimal", "number":
+		value, ok := actual.(float64)
+		if !ok {
+			return fmt.Sprintf("expected %s, got %s", rule.Match, kind(actual))
+		}
+		if rule.Match == "integer" && value != math.Trunc(value) {
+			return fmt.Sprintf("expected integer, got %s", encode(actual))
+		}
+	default:
+		return fmt.Sprintf("unsupported matching rule %q", rule.Match)
+	}
+	return ""
+}
+
+// parsePath parses a matching rule path, such as $.users[*].id or $['a b'].
+func parsePath(path string) []string {
+	var tokens []string
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '$':
+			tokens = append(tokens, "$")
+			i++
+		case '.':
+			end := strings.IndexAny(path[i+1:], ".[")
+			if end < 0 {
+				end = len(path) - i - 1
+			}
+			tokens = append(tokens, path[i+1:i+1+end])
+			i += end + 1
+		case '[':
+			end := strings.Index(path[i:], "]")
+			if end < 0 {
+				end = len(path) - i
+			}
+			tokens = append(tokens, strings.Trim(path[i+1:i+end], `'"`))
+			i += end + 1
+		default:
+			// Header names and other plain keys
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			tokens = append(tokens, path[i:i+end])
+			i += end
+		}
+	}
+	return tokens
+}
+
+func formatPath(tokens []string) string {
+	path := ""
+	for i, token := range tokens {
+		switch {
+		case i == 0:
+			path = token
+		case isIndex(token):
+			path += "[" + token + "]"
+		default:
+			path = childPath(path, token)
+		}
+	}
+	return path
+}
+
+func isIndex(token string) bool {
+	_, err := strconv.Atoi(token)
+	return err == nil
+}
+
+func kind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func encode(value interface{}) string {
+	buf, _ := json.Marshal(value)
+	return string(buf)
+}
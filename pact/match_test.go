@@ -0,0 +1,85 @@
+package pact
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestMatchBody(t *testing.T) {
+	_, rules := Rules(map[string]interface{}{
+		"id":    Like(1),
+		"email": Regex(`^\S+@\S+$`, "foo@bar.com"),
+		"tags":  EachLike(map[string]interface{}{"name": "foo"}, 2),
+		"user":  Like(map[string]interface{}{"name": "foo", "admin": false}),
+	})
+	expected := []byte(`{"id":1,"email":"foo@bar.com","tags":[{"name":"foo"},{"name":"foo"}],"user":{"name":"foo","admin":false},"plain":"value"}`)
+
+	cases := []struct {
+		body   string
+		errors []string
+	}{
+		{
+			`{"id":2,"email":"bar@foo.com","tags":[{"name":"bar"},{"name":"baz"},{"name":"foo"}],"user":{"name":"bar","admin":true},"plain":"value","extra":1}`,
+			nil,
+		},
+		{
+			`{"id":"2","email":"bar","tags":[{"name":1}],"user":{"name":"bar"},"plain":"other"}`,
+			[]string{
+				`$.id: expected number, got string`,
+				`$.email: expected "bar" to match "^\\S+@\\S+$"`,
+				`$.tags: expected at least 2 elements, got 1`,
+				`$.user.admin: missing field`,
+				`$.plain: expected "value", got "other"`,
+			},
+		},
+		{
+			`{"id":1,"email":"foo@bar.com","tags":[{"name":"foo"},{"name":1}],"user":{"name":"foo","admin":false},"plain":"value"}`,
+			[]string{`$.tags[1].name: expected string, got number`},
+		},
+	}
+
+	for _, c := range cases {
+		errors := MatchBody(expected, []byte(c.body), rules)
+		st.Expect(t, len(errors), len(c.errors))
+		for _, err := range c.errors {
+			st.Expect(t, contains(errors, err), true)
+		}
+	}
+}
+
+func TestMatchBodyStrict(t *testing.T) {
+	st.Expect(t, MatchBody([]byte(`[1,2]`), []byte(`[1,2]`), nil), []string(nil))
+	st.Expect(t, MatchBody([]byte(`[1,2]`), []byte(`[1,2,3]`), nil), []string{"$: expected array of length 2, got 3"})
+	st.Expect(t, MatchBody([]byte(`{"a":1}`), []byte(`foo`), nil)[0][:21], "$: invalid JSON body:")
+	st.Expect(t, MatchBody(nil, []byte(`foo`), nil), []string(nil))
+}
+
+func TestMatchHeader(t *testing.T) {
+	rules := map[string]*RuleSet{"Content-Type": {Matchers: []*Rule{{Match: "regex", Regex: "json"}}}}
+	st.Expect(t, MatchHeader("content-type", "application/json", "application/vnd+json", rules), nil)
+	st.Reject(t, MatchHeader("Content-Type", "application/json", "text/plain", rules), nil)
+	st.Expect(t, MatchHeader("X-Foo", "bar", "bar", rules), nil)
+	st.Reject(t, MatchHeader("X-Foo", "bar", "baz", rules), nil)
+}
+
+func TestMatchRulesCombine(t *testing.T) {
+	rules := map[string]*RuleSet{"$.id": {Combine: "OR", Matchers: []*Rule{{Match: "integer"}, {Match: "regex", Regex: `^\d+$`}}}}
+	st.Expect(t, len(MatchBody([]byte(`{"id":1}`), []byte(`{"id":"123"}`), rules)), 0)
+	st.Expect(t, len(MatchBody([]byte(`{"id":1}`), []byte(`{"id":1.5}`), rules)), 1)
+}
+
+func TestParsePath(t *testing.T) {
+	st.Expect(t, parsePath("$.users[*].id"), []string{"$", "users", "*", "id"})
+	st.Expect(t, parsePath("$['a b'][0]"), []string{"$", "a b", "0"})
+	st.Expect(t, formatPath([]string{"$", "users", "1", "a b"}), "$.users[1]['a b']")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package pact
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Matcher represents an example value matched by a rule
+// instead of strict equality, such as type or regular expression.
+// Matchers can be nested in maps and slices defining the response body.
+type Matcher struct {
+	// Rule stores the matching rule.
+	Rule Rule
+	// Value stores the example value.
+	Value interface{}
+}
+
+// Like matches any value of the same JSON type as the given example.
+// Nested values are matched by type too.
+func Like(example interface{}) *Matcher {
+	return &Matcher{Rule: Rule{Match: "type"}, Value: example}
+}
+
+// EachLike matches an array with at least min elements,
+// each one matching the given example by type.
+func EachLike(example interface{}, min int) *Matcher {
+	if min < 1 {
+		min = 1
+	}
+	values := make([]interface{}, min)
+	for i := range values {
+		values[i] = example
+	}
+	return &Matcher{Rule: Rule{Match: "type", Min: &min}, Value: values}
+}
+
+// Regex matches a string value against the given regular expression.
+// The example must match the regular expression.
+func Regex(pattern, example string) *Matcher {
+	if !regexp.MustCompile(pattern).MatchString(example) {
+		panic("pact: regex example " + strconv.Quote(example) + " does not match " + pattern)
+	}
+	return &Matcher{Rule: Rule{Match: "regex", Regex: pattern}, Value: example}
+}
+
+// MarshalJSON encodes the matcher example value as JSON.
+func (m *Matcher) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Value)
+}
+
+// Rules returns the example value and the body matching rules
+// defined by the matchers in the given value.
+func Rules(value interface{}) (interface{}, map[string]*RuleSet) {
+	rules := map[string]*RuleSet{}
+	return extractRules("$", value, rules), rules
+}
+
+func extractRules(path string, value interface{}, rules map[string]*RuleSet) interface{} {
+	if m, ok := value.(*Matcher); ok {
+		rule := m.Rule
+		rules[path] = &RuleSet{Matchers: []*Rule{&rule}}
+		if values, ok := m.Value.([]interface{}); ok && rule.Min != nil {
+			// Array elements are described by the first element rules
+			example := make([]interface{}, len(values))
+			for i, v := range values {
+				example[i] = extractRules(path+"[*]", v, rules)
+			}
+			return example
+		}
+		return extractRules(path, m.Value, rules)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		example := make(map[string]interface{}, len(keys))
+		for _, key := range keys {
+			example[key] = extractRules(childPath(path, key), v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())).Interface(), rules)
+		}
+		return example
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return value
+		}
+		example := make([]interface{}, v.Len())
+		for i := range example {
+			example[i] = extractRules(path+"["+strconv.Itoa(i)+"]", v.Index(i).Interface(), rules)
+		}
+		return example
+	}
+	return value
+}
+
+// identRegexp matches path keys that can be used with dot notation.
+var identRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func childPath(path, key string) string {
+	if identRegexp.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "['" + key + "']"
+}
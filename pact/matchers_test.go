@@ -0,0 +1,42 @@
+package pact
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestRules(t *testing.T) {
+	example, rules := Rules(map[string]interface{}{
+		"id":    Like(1),
+		"email": Regex(`^\S+@\S+$`, "foo@bar.com"),
+		"tags":  EachLike(map[string]interface{}{"name": Like("foo")}, 2),
+		"plain": "value",
+		"a b":   []interface{}{Like(true)},
+	})
+
+	body, _ := json.Marshal(example)
+	st.Expect(t, string(body), `{"a b":[true],"email":"foo@bar.com","id":1,"plain":"value","tags":[{"name":"foo"},{"name":"foo"}]}`)
+
+	min := 2
+	st.Expect(t, len(rules), 5)
+	st.Expect(t, rules["$.id"].Matchers, []*Rule{{Match: "type"}})
+	st.Expect(t, rules["$.email"].Matchers, []*Rule{{Match: "regex", Regex: `^\S+@\S+$`}})
+	st.Expect(t, rules["$.tags"].Matchers, []*Rule{{Match: "type", Min: &min}})
+	st.Expect(t, rules["$.tags[*].name"].Matchers, []*Rule{{Match: "type"}})
+	st.Expect(t, rules["$['a b'][0]"].Matchers, []*Rule{{Match: "type"}})
+}
+
+func TestMatcherJSON(t *testing.T) {
+	body, err := json.Marshal(map[string]interface{}{"id": Like(1)})
+	st.Expect(t, err, nil)
+	st.Expect(t, string(body), `{"id":1}`)
+}
+
+func TestRegexInvalidExample(t *testing.T) {
+	defer func() {
+		st.Reject(t, recover(), nil)
+	}()
+	Regex(`^\d+$`, "foo")
+}
@@ -0,0 +1,153 @@
+// Package pact implements consumer-driven contract testing
+// compatible with the Pact specification v3.
+//
+// Consumer tests record the HTTP interactions performed by baloo
+// as pact files via the Recorder plugin, while provider tests
+// replay and verify the pact file interactions via Verifier.
+// Both sides work locally based on files, without a pact broker.
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+// SpecificationVersion stores the supported Pact specification version.
+const SpecificationVersion = "3.0.0"
+
+// Pact represents a Pact contract file between a consumer and a provider.
+type Pact struct {
+	Consumer     Pacticipant    `json:"consumer"`
+	Provider     Pacticipant    `json:"provider"`
+	Interactions []*Interaction `json:"interactions"`
+	Metadata     Metadata       `json:"metadata"`
+}
+
+// Pacticipant represents a pact consumer or provider.
+type Pacticipant struct {
+	Name string `json:"name"`
+}
+
+// Metadata represents the pact file metadata.
+type Metadata struct {
+	PactSpecification PactSpecification `json:"pactSpecification"`
+}
+
+// PactSpecification represents the pact file specification version.
+type PactSpecification struct {
+	Version string `json:"version"`
+}
+
+// Interaction represents an HTTP request and the expected response.
+type Interaction struct {
+	Description    string          `json:"description"`
+	ProviderStates []ProviderState `json:"providerStates,omitempty"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+// ProviderState represents a state the provider must be in for an interaction.
+type ProviderState struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// Request represents the interaction HTTP request.
+type Request struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Body    json.RawMessage     `json:"body,omitempty"`
+}
+
+// Response represents the interaction expected HTTP response.
+type Response struct {
+	Status        int               `json:"status"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Body          json.RawMessage   `json:"body,omitempty"`
+	MatchingRules MatchingRules     `json:"matchingRules,omitempty"`
+}
+
+// MatchingRules stores the matching rules by category (body, header...)
+// and path, such as $.users[*].id for the body or Content-Type for headers.
+type MatchingRules map[string]map[string]*RuleSet
+
+// RuleSet represents the matching rules applied to a given path.
+type RuleSet struct {
+	Combine  string  `json:"combine,omitempty"`
+	Matchers []*Rule `json:"matchers"`
+}
+
+// Rule represents a matching rule.
+type Rule struct {
+	Match string `json:"match"`
+	Regex string `json:"regex,omitempty"`
+	Min   *int   `json:"min,omitempty"`
+	Max   *int   `json:"max,omitempty"`
+}
+
+// New creates a new pact between the given consumer and provider.
+func New(consumer, provider string) *Pact {
+	return &Pact{
+		Consumer:     Pacticipant{Name: consumer},
+		Provider:     Pacticipant{Name: provider},
+		Interactions: []*Interaction{},
+		Metadata:     Metadata{PactSpecification: PactSpecification{Version: SpecificationVersion}},
+	}
+}
+
+// Read reads a pact from the given JSON reader.
+func Read(r io.Reader) (*Pact, error) {
+	p := &Pact{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReadFile reads a pact from the given file path.
+func ReadFile(path string) (*Pact, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Write writes the pact as JSON in the given writer.
+func (p *Pact) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// WriteFile writes the pact as JSON in the given file path.
+func (p *Pact) WriteFile(path string) error {
+	buf := &bytes.Buffer{}
+	if err := p.Write(buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// WriteDir writes the pact in the given directory,
+// using the conventional "<consumer>-<provider>.json" file name.
+func (p *Pact) WriteDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return p.WriteFile(filepath.Join(dir, p.FileName()))
+}
+
+// FileName returns the conventional pact file name.
+func (p *Pact) FileName() string {
+	return httputil.FileName(p.Consumer.Name+"-"+p.Provider.Name, ".json")
+}
@@ -0,0 +1,40 @@
+package pact
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestPactFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pact")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	p := New("web app", "users")
+	p.Interactions = append(p.Interactions, &Interaction{
+		Description: "get user",
+		Request:     Request{Method: "GET", Path: "/users/1"},
+		Response:    Response{Status: 200, Body: []byte(`{"id":1}`)},
+	})
+	st.Expect(t, p.FileName(), "web_app-users.json")
+	st.Expect(t, p.WriteDir(dir), nil)
+
+	read, err := ReadFile(filepath.Join(dir, "web_app-users.json"))
+	st.Expect(t, err, nil)
+	st.Expect(t, read.Consumer.Name, "web app")
+	st.Expect(t, read.Provider.Name, "users")
+	st.Expect(t, read.Metadata.PactSpecification.Version, "3.0.0")
+	st.Expect(t, len(read.Interactions), 1)
+	st.Expect(t, read.Interactions[0].Description, "get user")
+	st.Expect(t, strings.Join(strings.Fields(string(read.Interactions[0].Response.Body)), ""), `{"id":1}`)
+}
+
+func TestReadFileError(t *testing.T) {
+	_, err := ReadFile("missing.json")
+	st.Reject(t, err, nil)
+}
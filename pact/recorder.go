@@ -0,0 +1,309 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/h2non/baloo.v3/har"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+const (
+	optionsKey = "$pact.options"
+	bodyKey    = "$pact.request.body"
+)
+
+// ignoredHeaders stores the request header fields not recorded in interactions.
+var ignoredHeaders = []string{"Accept-Encoding", "Content-Length", "Host", "User-Agent"}
+
+// options stores the interaction options defined per request.
+type options struct {
+	description string
+	states      []ProviderState
+	body        interface{}
+	headers     map[string]interface{}
+}
+
+// Recorder implements a gentleman plugin recording every HTTP
+// exchange as pact interaction, from the consumer side.
+type Recorder struct {
+	*plugin.Layer
+
+	// Redact stores the request header fields whose values are redacted
+	// in the recorded interactions, such as Authorization.
+	Redact []string
+
+	mutex sync.Mutex
+	pact  *Pact
+}
+
+// NewRecorder creates a new pact recorder plugin for the given consumer and provider.
+func NewRecorder(consumer, provider string) *Recorder {
+	r := &Recorder{
+		Layer:  plugin.New(),
+		Redact: append([]string(nil), httputil.RedactedHeaders...),
+		pact:   New(consumer, provider),
+	}
+	r.SetHandlers(plugin.Handlers{
+		"before dial": r.beforeDial,
+		"response":    r.response,
+	})
+	return r
+}
+
+// Pact returns the pact containing the recorded interactions.
+func (r *Recorder) Pact() *Pact {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	p := *r.pact
+	p.Interactions = append([]*Interaction{}, r.pact.Interactions...)
+	return &p
+}
+
+// WriteFile writes the pact in the given file path.
+func (r *Recorder) WriteFile(path string) error {
+	return r.Pact().WriteFile(path)
+}
+
+// WriteDir writes the pact in the given directory,
+// using the conventional "<consumer>-<provider>.json" file name.
+func (r *Recorder) WriteDir(dir string) error {
+	return r.Pact().WriteDir(dir)
+}
+
+// Flush removes the recorded interactions.
+func (r *Recorder) Flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pact.Interactions = []*Interaction{}
+}
+
+// Description defines the recorded interaction description.
+// Defaults to the test name, if bound, or the request method and path.
+func Description(text string) plugin.Plugin {
+	return withOptions(func(opts *options) {
+		opts.description = text
+	})
+}
+
+// Given defines a provider state for the recorded interaction,
+// with optional state params.
+func Given(state string, params ...map[string]interface{}) plugin.Plugin {
+	s := ProviderState{Name: state}
+	if len(params) > 0 {
+		s.Params = params[0]
+	}
+	return withOptions(func(opts *options) {
+		opts.states = append(opts.states, s)
+	})
+}
+
+// Body defines the response body matching rules via matchers,
+// such as Like, EachLike or Regex, based on the given body template.
+// Without a body template, the recorded response body must be equal.
+func Body(template interface{}) plugin.Plugin {
+	return withOptions(func(opts *options) {
+		opts.body = template
+	})
+}
+
+// Header records the given response header field in the interaction.
+// The value can be a string or a Regex matcher.
+// By default, only the Content-Type response header is recorded.
+func Header(name string, value interface{}) plugin.Plugin {
+	return withOptions(func(opts *options) {
+		if opts.headers == nil {
+			opts.headers = map[string]interface{}{}
+		}
+		opts.headers[http.CanonicalHeaderKey(name)] = value
+	})
+}
+
+func withOptions(fn func(*options)) plugin.Plugin {
+	return plugin.NewRequestPlugin(func(ctx *context.Context, h context.Handler) {
+		opts, ok := ctx.Get(optionsKey).(*options)
+		if !ok {
+			opts = &options{}
+			ctx.Set(optionsKey, opts)
+		}
+		fn(opts)
+		h.Next(ctx)
+	})
+}
+
+func (r *Recorder) beforeDial(ctx *context.Context, h context.Handler) {
+	body, err := httputil.ReadRequestBody(ctx.Request)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	ctx.Set(bodyKey, body)
+	h.Next(ctx)
+}
+
+func (r *Recorder) response(ctx *context.Context, h context.Handler) {
+	body, err := httputil.ReadResponseBody(ctx.Response)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+
+	opts, _ := ctx.Get(optionsKey).(*options)
+	if opts == nil {
+		opts = &options{}
+	}
+	reqBody, _ := ctx.Get(bodyKey).([]byte)
+	redact, _ := ctx.Get(har.RedactKey).([]string)
+	redact = append(append([]string(nil), r.Redact...), redact...)
+
+	interaction := &Interaction{
+		Description:    opts.description,
+		ProviderStates: opts.states,
+		Request:        newRequest(ctx.Request, reqBody, redact),
+		Response:       newResponse(ctx.Response, body, opts),
+	}
+	if interaction.Description == "" {
+		interaction.Description = ctx.GetString(har.CommentKey)
+	}
+	if interaction.Description == "" {
+		interaction.Description = ctx.Request.Method + " " + ctx.Request.URL.Path
+	}
+
+	r.mutex.Lock()
+	interaction.Description = r.uniqueDescription(interaction.Description)
+	r.pact.Interactions = append(r.pact.Interactions, interaction)
+	r.mutex.Unlock()
+
+	h.Next(ctx)
+}
+
+// uniqueDescription returns a description not used by any recorded interaction,
+// since interactions are identified by description.
+func (r *Recorder) uniqueDescription(description string) string {
+	used := map[string]bool{}
+	for _, interaction := range r.pact.Interactions {
+		used[interaction.Description] = true
+	}
+	unique := description
+	for i := 2; used[unique]; i++ {
+		unique = description + " #" + strconv.Itoa(i)
+	}
+	return unique
+}
+
+func newRequest(req *http.Request, body []byte, redact []string) Request {
+	r := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Body:   rawBody(req.Header, body),
+	}
+	if query := req.URL.Query(); len(query) > 0 {
+		r.Query = query
+	}
+
+	for key, values := range req.Header {
+		if isIgnored(key) {
+			continue
+		}
+		if r.Headers == nil {
+			r.Headers = map[string]string{}
+		}
+		r.Headers[key] = strings.Join(values, ", ")
+		if httputil.IsRedacted(key, redact) {
+			r.Headers[key] = httputil.Redacted
+		}
+	}
+	return r
+}
+
+func newResponse(res *http.Response, body []byte, opts *options) Response {
+	r := Response{
+		Status:        res.StatusCode,
+		Headers:       map[string]string{},
+		Body:          rawBody(res.Header, body),
+		MatchingRules: MatchingRules{},
+	}
+
+	if value := res.Header.Get("Content-Type"); value != "" {
+		r.Headers["Content-Type"] = value
+	}
+
+	for _, key := range httputil.SortedKeys(opts.headers) {
+		r.Headers[key] = strings.Join(res.Header[key], ", ")
+		if m, ok := opts.headers[key].(*Matcher); ok {
+			if r.MatchingRules["header"] == nil {
+				r.MatchingRules["header"] = map[string]*RuleSet{}
+			}
+			rule := m.Rule
+			r.MatchingRules["header"][key] = &RuleSet{Matchers: []*Rule{&rule}}
+		} else {
+			r.Headers[key] = fmt.Sprint(opts.headers[key])
+		}
+	}
+
+	if opts.body != nil {
+		if _, rules := Rules(opts.body); len(rules) > 0 {
+			r.MatchingRules["body"] = rules
+		}
+	}
+
+	if len(r.Headers) == 0 {
+		r.Headers = nil
+	}
+	if len(r.MatchingRules) == 0 {
+		r.MatchingRules = nil
+	}
+	return r
+}
+
+// rawBody returns the given body as JSON, encoding non-JSON bodies as JSON string.
+// The body kind is defined by the recorded Content-Type header.
+func rawBody(header http.Header, body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if isJSON(header.Get("Content-Type")) && json.Valid(body) {
+		buf := &bytes.Buffer{}
+		if err := json.Compact(buf, body); err == nil {
+			return buf.Bytes()
+		}
+	}
+	text, _ := json.Marshal(string(body))
+	return text
+}
+
+// isJSON reports whether the given Content-Type defines a JSON body.
+func isJSON(contentType string) bool {
+	return strings.Contains(contentType, "json")
+}
+
+// textBody returns the text of the given recorded body, if it was recorded
+// as JSON string based on the Content-Type header stored in the given headers.
+func textBody(headers map[string]string, body json.RawMessage) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") && isJSON(value) {
+			return "", false
+		}
+	}
+	var text string
+	if err := json.Unmarshal(body, &text); err != nil {
+		return "", false
+	}
+	return text, true
+}
+
+func isIgnored(key string) bool {
+	for _, name := range ignoredHeaders {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
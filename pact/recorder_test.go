@@ -0,0 +1,67 @@
+package pact
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/mock"
+)
+
+func TestRecorder(t *testing.T) {
+	srv := mock.NewServer(
+		mock.Get("/users/:id").Reply(200).SetHeader("X-Version", "1.2").JSON(`{"id":1,"name":"foo"}`),
+		mock.Post("/users").Reply(201).BodyString("created"),
+	)
+	defer srv.Close()
+
+	rec := NewRecorder("web", "users")
+	test := baloo.New(srv.URL).Use(rec)
+
+	test.Get("/users/1").
+		AddQuery("fields", "name").
+		SetHeader("Accept", "application/json").
+		SetHeader("Authorization", "Bearer secret").
+		Use(Given("user exists", map[string]interface{}{"id": 1})).
+		Use(Body(map[string]interface{}{"id": Like(1)})).
+		Use(Header("X-Version", Regex(`^\d+\.\d+$`, "1.0"))).
+		Expect(t).
+		Status(200).
+		Done()
+
+	test.Post("/users").
+		Use(Description("create user")).
+		JSON(map[string]string{"name": "foo"}).
+		Expect(t).
+		Status(201).
+		Done()
+
+	test.Post("/users").Expect(t).Status(201).Done()
+
+	p := rec.Pact()
+	st.Expect(t, len(p.Interactions), 3)
+
+	get := p.Interactions[0]
+	st.Expect(t, get.Description, "TestRecorder")
+	st.Expect(t, get.ProviderStates, []ProviderState{{Name: "user exists", Params: map[string]interface{}{"id": 1}}})
+	st.Expect(t, get.Request.Method, "GET")
+	st.Expect(t, get.Request.Path, "/users/1")
+	st.Expect(t, get.Request.Query, map[string][]string{"fields": {"name"}})
+	st.Expect(t, get.Request.Headers, map[string]string{"Accept": "application/json", "Authorization": "[REDACTED]"})
+	st.Expect(t, get.Response.Status, 200)
+	st.Expect(t, get.Response.Headers, map[string]string{"Content-Type": "application/json", "X-Version": "1.2"})
+	st.Expect(t, string(get.Response.Body), `{"id":1,"name":"foo"}`)
+	st.Expect(t, get.Response.MatchingRules["body"]["$.id"].Matchers, []*Rule{{Match: "type"}})
+	st.Expect(t, get.Response.MatchingRules["header"]["X-Version"].Matchers, []*Rule{{Match: "regex", Regex: `^\d+\.\d+$`}})
+
+	post := p.Interactions[1]
+	st.Expect(t, post.Description, "create user")
+	st.Expect(t, string(post.Request.Body), `{"name":"foo"}`)
+	st.Expect(t, string(post.Response.Body), `"created"`)
+	st.Expect(t, post.Response.MatchingRules, MatchingRules(nil))
+
+	st.Expect(t, p.Interactions[2].Description, "TestRecorder #2")
+
+	rec.Flush()
+	st.Expect(t, len(rec.Pact().Interactions), 0)
+}
@@ -0,0 +1,129 @@
+package pact
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+// StateHandler sets up the provider for the given provider state.
+type StateHandler func(state ProviderState) error
+
+// Verifier replays the pact interactions against the provider
+// using a baloo client, verifying the responses.
+type Verifier struct {
+	// Client stores the baloo client pointing to the provider.
+	Client *baloo.Client
+
+	// States stores the provider state handlers by state name.
+	States map[string]StateHandler
+}
+
+// NewVerifier creates a new provider verifier using the given client.
+func NewVerifier(cli *baloo.Client) *Verifier {
+	return &Verifier{Client: cli, States: map[string]StateHandler{}}
+}
+
+// State registers a provider state handler by state name.
+func (v *Verifier) State(name string, fn StateHandler) *Verifier {
+	v.States[name] = fn
+	return v
+}
+
+// VerifyFile reads and verifies the pact file in the given path.
+func (v *Verifier) VerifyFile(t *testing.T, path string) {
+	p, err := ReadFile(path)
+	if err != nil {
+		t.Errorf("pact: cannot read pact file: %s", err)
+		return
+	}
+	v.Verify(t, p)
+}
+
+// Verify verifies every pact interaction as subtest named by the interaction description.
+func (v *Verifier) Verify(t *testing.T, p *Pact) {
+	for _, interaction := range p.Interactions {
+		interaction := interaction
+		t.Run(interaction.Description, func(t *testing.T) {
+			v.verify(t, interaction)
+		})
+	}
+}
+
+func (v *Verifier) verify(t *testing.T, interaction *Interaction) {
+	for _, state := range interaction.ProviderStates {
+		fn, ok := v.States[state.Name]
+		if !ok {
+			t.Logf("pact: missing provider state handler: %s", state.Name)
+			continue
+		}
+		if err := fn(state); err != nil {
+			t.Errorf("pact: provider state %q failed: %s", state.Name, err)
+			return
+		}
+	}
+
+	req := interaction.Request
+	r := v.Client.Request().Method(req.Method).Path(req.Path)
+	for _, key := range httputil.SortedKeys(req.Query) {
+		for _, value := range req.Query[key] {
+			r.AddQuery(key, value)
+		}
+	}
+	for key, value := range req.Headers {
+		// Redacted values, such as credentials, are defined by the client
+		if value != httputil.Redacted {
+			r.SetHeader(key, value)
+		}
+	}
+	if len(req.Body) > 0 {
+		if text, ok := textBody(req.Headers, req.Body); ok {
+			r.BodyString(text)
+		} else {
+			r.Body(bytes.NewReader(req.Body))
+		}
+	}
+
+	r.Expect(t).
+		Status(interaction.Response.Status).
+		AssertFunc(MatchResponse(interaction.Response)).
+		Done()
+}
+
+// MatchResponse returns an assertion function matching the
+// response headers and body against the expected pact response.
+func MatchResponse(expected Response) func(*http.Response, *http.Request) error {
+	return func(res *http.Response, req *http.Request) error {
+		var mismatches []string
+
+		for _, key := range httputil.SortedKeys(expected.Headers) {
+			actual := strings.Join(res.Header[http.CanonicalHeaderKey(key)], ", ")
+			if err := MatchHeader(key, expected.Headers[key], actual, expected.MatchingRules["header"]); err != nil {
+				mismatches = append(mismatches, "header "+err.Error())
+			}
+		}
+
+		body, err := httputil.ReadResponseBody(res)
+		if err != nil {
+			return err
+		}
+
+		if text, ok := textBody(expected.Headers, expected.Body); ok {
+			if string(body) != text {
+				mismatches = append(mismatches, "body: expected "+string(expected.Body)+", got "+encode(string(body)))
+			}
+		} else {
+			mismatches = append(mismatches, MatchBody(expected.Body, body, expected.MatchingRules["body"])...)
+		}
+
+		if len(mismatches) > 0 {
+			return errors.New("pact: response mismatch:\n\t- " + strings.Join(mismatches, "\n\t- "))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package pact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/mock"
+)
+
+func TestVerifier(t *testing.T) {
+	consumer := mock.NewServer(
+		mock.Get("/users/:id").Reply(200).JSON(`{"id":1,"name":"foo","tags":["a","b"]}`),
+		mock.Post("/users").MatchJSON(`{"name":"foo"}`).Reply(201).BodyString("created"),
+		mock.Put("/users/1/name").MatchJSON(`"foo"`).Reply(200),
+	)
+	defer consumer.Close()
+
+	rec := NewRecorder("web", "users")
+	test := baloo.New(consumer.URL).Use(rec)
+	test.Get("/users/1").
+		Use(Given("user exists")).
+		Use(Body(map[string]interface{}{"id": Like(1), "name": Like("foo"), "tags": EachLike("a", 1)})).
+		Expect(t).Status(200).Done()
+	test.Post("/users").JSON(map[string]string{"name": "foo"}).Expect(t).Status(201).Done()
+	test.Put("/users/1/name").
+		SetHeader("Authorization", "Bearer consumer").
+		JSON(`"foo"`).
+		Expect(t).Status(200).Done()
+
+	provider := mock.NewServer(
+		mock.Get("/users/1").Reply(200).JSON(`{"id":2,"name":"bar","tags":["c"],"extra":true}`),
+		mock.Post("/users").MatchJSON(`{"name":"foo"}`).Reply(201).BodyString("created"),
+		mock.Put("/users/1/name").MatchJSON(`"foo"`).MatchHeader("Authorization", `^Bearer provider$`).Reply(200),
+	)
+	defer provider.Close()
+
+	var states []string
+	NewVerifier(baloo.New(provider.URL).SetHeader("Authorization", "Bearer provider")).
+		State("user exists", func(state ProviderState) error {
+			states = append(states, state.Name)
+			return nil
+		}).
+		Verify(t, rec.Pact())
+
+	st.Expect(t, states, []string{"user exists"})
+	provider.AssertAllCalled(t)
+}
+
+func TestMatchResponse(t *testing.T) {
+	_, rules := Rules(map[string]interface{}{"id": Like(1)})
+	expected := Response{
+		Status:        200,
+		Headers:       map[string]string{"Content-Type": "application/json"},
+		Body:          []byte(`{"id":1,"name":"foo"}`),
+		MatchingRules: MatchingRules{"body": rules},
+	}
+
+	newResponse := func(kind, body string) *http.Response {
+		res := &http.Response{Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+		res.Header.Set("Content-Type", kind)
+		return res
+	}
+
+	match := MatchResponse(expected)
+	st.Expect(t, match(newResponse("application/json", `{"id":2,"name":"foo"}`), nil), nil)
+
+	err := match(newResponse("text/plain", `{"id":"2","name":"bar"}`), nil)
+	st.Reject(t, err, nil)
+	st.Expect(t, strings.Contains(err.Error(), `header Content-Type: expected "application/json", got "text/plain"`), true)
+	st.Expect(t, strings.Contains(err.Error(), `$.id: expected number, got string`), true)
+	st.Expect(t, strings.Contains(err.Error(), `$.name: expected "foo", got "bar"`), true)
+
+	text := MatchResponse(Response{Body: []byte(`"created"`)})
+	st.Expect(t, text(newResponse("text/plain", "created"), nil), nil)
+	st.Reject(t, text(newResponse("text/plain", "other"), nil), nil)
+
+	// JSON string bodies are matched as JSON based on the Content-Type
+	str := MatchResponse(Response{Headers: map[string]string{"content-type": "application/json"}, Body: []byte(`"created"`)})
+	st.Expect(t, str(newResponse("application/json", `"created"`), nil), nil)
+	st.Reject(t, str(newResponse("application/json", "created"), nil), nil)
+}
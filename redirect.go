@@ -0,0 +1,75 @@
+package baloo
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// FollowRedirects enables or disables following redirects by client requests.
+// If disabled, the redirect response is returned as is.
+// Redirects are followed by default, up to 10 times.
+func (c *Client) FollowRedirects(follow bool) *Client {
+	return c.Use(redirectPolicy(follow, 10))
+}
+
+// MaxRedirects defines the maximum number of redirects followed by client requests.
+// Requests fail when exceeded.
+func (c *Client) MaxRedirects(max int) *Client {
+	return c.Use(redirectPolicy(true, max))
+}
+
+// FollowRedirects enables or disables following redirects.
+// If disabled, the redirect response is returned as is.
+func (r *Request) FollowRedirects(follow bool) *Request {
+	return r.Use(redirectPolicy(follow, 10))
+}
+
+// MaxRedirects defines the maximum number of redirects followed.
+// The request fails when exceeded.
+func (r *Request) MaxRedirects(max int) *Request {
+	return r.Use(redirectPolicy(true, max))
+}
+
+// StatusRedirect asserts the response status code
+// as redirect (301, 302, 303, 307 or 308).
+func (e *Expect) StatusRedirect() *Expect {
+	e.AssertFunc(assert.StatusRedirect())
+	return e
+}
+
+// RedirectCount asserts the number of redirects followed.
+func (e *Expect) RedirectCount(count int) *Expect {
+	e.AssertFunc(assert.RedirectCount(count))
+	return e
+}
+
+// RedirectChain asserts the URLs followed by redirects, in order.
+// URLs starting with "/" are compared with the redirect URL path and query.
+func (e *Expect) RedirectChain(urls ...string) *Expect {
+	e.AssertFunc(assert.RedirectChain(urls...))
+	return e
+}
+
+// RedirectChain returns the redirect responses followed to obtain the given response, in order.
+func RedirectChain(res *http.Response) []*http.Response {
+	return assert.Redirects(res)
+}
+
+func redirectPolicy(follow bool, max int) plugin.Plugin {
+	return plugin.NewRequestPlugin(func(ctx *context.Context, h context.Handler) {
+		ctx.Client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if !follow {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > max {
+				return fmt.Errorf("stopped after %d redirects", max)
+			}
+			return nil
+		}
+		h.Next(ctx)
+	})
+}
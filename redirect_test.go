@@ -0,0 +1,94 @@
+package baloo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func createRedirectServer() *httptest.Server {
+	redirects := map[string]struct {
+		code     int
+		location string
+	}{
+		"/a": {301, "/b"},
+		"/b": {303, "/c?foo=bar"},
+		"/c": {307, "/d"},
+		"/d": {308, "/e"},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if redirect, ok := redirects[r.URL.Path]; ok {
+			http.Redirect(w, r, redirect.location, redirect.code)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+}
+
+func TestRedirectChain(t *testing.T) {
+	ts := createRedirectServer()
+	defer ts.Close()
+
+	res, err := New(ts.URL).Get("/a").
+		Expect(t).
+		Status(200).
+		BodyEquals("/e").
+		RedirectCount(4).
+		RedirectChain("/b", "/c?foo=bar", ts.URL+"/d", "/e").
+		Send()
+
+	st.Expect(t, err, nil)
+	chain := RedirectChain(res.RawResponse)
+	st.Expect(t, len(chain), 4)
+	st.Expect(t, chain[0].StatusCode, 301)
+	st.Expect(t, chain[0].Request.URL.Path, "/a")
+	st.Expect(t, chain[3].StatusCode, 308)
+}
+
+func TestFollowRedirects(t *testing.T) {
+	ts := createRedirectServer()
+	defer ts.Close()
+
+	cli := New(ts.URL).FollowRedirects(false)
+	cli.Get("/a").
+		Expect(t).
+		Status(301).
+		StatusRedirect().
+		RedirectTo("/b").
+		RedirectCount(0).
+		Done()
+
+	// Request policy takes precedence
+	cli.Get("/c").
+		FollowRedirects(true).
+		Expect(t).
+		Status(200).
+		RedirectChain("/d", "/e").
+		Done()
+}
+
+func TestMaxRedirects(t *testing.T) {
+	ts := createRedirectServer()
+	defer ts.Close()
+
+	cli := New(ts.URL).MaxRedirects(2)
+	cli.Get("/c").Expect(t).Status(200).RedirectCount(2).Done()
+
+	_, err := cli.Get("/a").Send()
+	st.Expect(t, strings.Contains(err.Error(), "stopped after 2 redirects"), true)
+
+	cli.Get("/a").MaxRedirects(4).Expect(t).Status(200).Done()
+}
+
+func TestStatusRedirect(t *testing.T) {
+	ts := createRedirectServer()
+	defer ts.Close()
+
+	cli := New(ts.URL).FollowRedirects(false)
+	for _, path := range []string{"/a", "/b", "/c", "/d"} {
+		cli.Get(path).Expect(t).StatusRedirect().Done()
+	}
+}
@@ -0,0 +1,86 @@
+package baloo
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+)
+
+// AssertFactory represents a parameterized assertion function
+// which creates the assertion based on the given arguments.
+type AssertFactory func(args ...interface{}) assert.Func
+
+// Registry stores assertion functions by alias name.
+// Registries are safe for concurrent use and can inherit
+// assertion functions from a parent registry.
+type Registry struct {
+	mutex  sync.RWMutex
+	parent *Registry
+	funcs  map[string]AssertFactory
+}
+
+// NewRegistry creates a new assertion registry
+// inheriting from the given optional parent registry.
+func NewRegistry(parent *Registry) *Registry {
+	return &Registry{parent: parent, funcs: make(map[string]AssertFactory)}
+}
+
+// SetParent defines the parent registry used to lookup
+// assertion functions not registered at the current level.
+func (r *Registry) SetParent(parent *Registry) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.parent = parent
+	return r
+}
+
+// Add registers a new assertion function by alias name.
+func (r *Registry) Add(name string, fn assert.Func) *Registry {
+	return r.AddFactory(name, func(args ...interface{}) assert.Func {
+		return fn
+	})
+}
+
+// AddFactory registers a new parameterized assertion function by alias name.
+func (r *Registry) AddFactory(name string, factory AssertFactory) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.funcs[name] = factory
+	return r
+}
+
+// Get looks up the assertion factory by alias name
+// at the current level, then in the parent registry.
+func (r *Registry) Get(name string) (AssertFactory, bool) {
+	r.mutex.RLock()
+	factory, ok := r.funcs[name]
+	parent := r.parent
+	r.mutex.RUnlock()
+
+	if !ok && parent != nil {
+		return parent.Get(name)
+	}
+	return factory, ok
+}
+
+// Func creates the assertion function registered by alias name
+// with the given arguments. If no assertion function is registered,
+// the returned assertion function fails with an error.
+func (r *Registry) Func(name string, args ...interface{}) assert.Func {
+	factory, ok := r.Get(name)
+	if !ok {
+		return func(res *http.Response, req *http.Request) error {
+			return fmt.Errorf("no assertion function registered by alias: %s", name)
+		}
+	}
+	return factory(args...)
+}
+
+// Flush removes the registered assertion functions at the current level.
+func (r *Registry) Flush() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.funcs = make(map[string]AssertFactory)
+}
@@ -0,0 +1,92 @@
+package baloo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3/assert"
+)
+
+func assertStatusCode(args ...interface{}) assert.Func {
+	return func(res *http.Response, req *http.Request) error {
+		if res.StatusCode != args[0].(int) {
+			return errors.New("status code mismatch")
+		}
+		return nil
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry(nil)
+	reg.Add("foo", assertStatus)
+	_, ok := reg.Get("foo")
+	st.Expect(t, ok, true)
+	_, ok = reg.Get("bar")
+	st.Expect(t, ok, false)
+
+	res := &http.Response{StatusCode: 200}
+	st.Expect(t, reg.Func("foo")(res, nil), nil)
+	st.Reject(t, reg.Func("bar")(res, nil), nil)
+
+	reg.Flush()
+	_, ok = reg.Get("foo")
+	st.Expect(t, ok, false)
+}
+
+func TestRegistryFactory(t *testing.T) {
+	reg := NewRegistry(nil)
+	reg.AddFactory("status", assertStatusCode)
+
+	res := &http.Response{StatusCode: 204}
+	st.Expect(t, reg.Func("status", 204)(res, nil), nil)
+	st.Reject(t, reg.Func("status", 200)(res, nil), nil)
+}
+
+func TestRegistryInheritance(t *testing.T) {
+	parent := NewRegistry(nil)
+	reg := NewRegistry(parent)
+	parent.Add("foo", assertStatus)
+	_, ok := reg.Get("foo")
+	st.Expect(t, ok, true)
+
+	reg.SetParent(nil)
+	_, ok = reg.Get("foo")
+	st.Expect(t, ok, false)
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	reg := NewRegistry(NewRegistry(nil))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("foo%d", i)
+			reg.Add(name, assertStatus)
+			reg.Get(name)
+			reg.Func(name)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestClientAssertFunc(t *testing.T) {
+	parent := New("")
+	parent.AddAssertFunc("foo", assertStatus)
+	parent.AddAssertFactory("status", assertStatusCode)
+	cli := New("").UseParent(parent)
+
+	res := &http.Response{StatusCode: 200}
+	exp := NewExpect(cli.Get("/foo")).Assert("foo").AssertWith("status", 200)
+	st.Expect(t, exp.run(res, nil), nil)
+
+	exp = NewExpect(cli.Get("/foo")).AssertWith("status", 201)
+	st.Reject(t, exp.run(res, nil), nil)
+
+	exp = NewExpect(New("").Get("/foo")).Assert("foo")
+	st.Reject(t, exp.run(res, nil), nil)
+}
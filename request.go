@@ -1,9 +1,13 @@
 package baloo
 
 import (
+	"bytes"
 	"io"
+	"io/ioutil"
 	"net/http"
 
+	"gopkg.in/h2non/baloo.v3/codec"
+	"gopkg.in/h2non/baloo.v3/har"
 	"gopkg.in/h2non/gentleman.v2"
 	"gopkg.in/h2non/gentleman.v2/context"
 	"gopkg.in/h2non/gentleman.v2/plugin"
@@ -27,12 +31,19 @@ type Request struct {
 
 	// Request stores the reference to gentleman.Request instance.
 	Request *gentleman.Request
+
+	// sent stores the request context once the request was sent.
+	sent *context.Context
+
+	// recording stores if the request is recorded by the global HARRecorder.
+	recording bool
 }
 
 // NewRequest creates a new Request entity.
 func NewRequest() *Request {
 	req := gentleman.NewRequest()
 	req.SetHeader("User-Agent", UserAgent)
+	req.UseHandler("before dial", captureRequestBody)
 	return &Request{Request: req}
 }
 
@@ -205,10 +216,53 @@ func (r *Request) Files(files []multipart.FormFile) *Request {
 	return r
 }
 
+// Encode serializes and defines the request body with the codec registered
+// for the given content type, such as application/x-protobuf, application/msgpack,
+// application/cbor or application/yaml, and defines the Content-Type header.
+// Formats other than JSON require importing their codec subpackage,
+// such as gopkg.in/h2non/baloo.v3/codec/yaml.
+func (r *Request) Encode(contentType string, data interface{}) *Request {
+	r.Request.UseRequest(func(ctx *context.Context, h context.Handler) {
+		buf, err := codec.Marshal(contentType, data)
+		if err != nil {
+			h.Error(ctx, err)
+			return
+		}
+
+		ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(buf))
+		ctx.Request.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(buf)), nil
+		}
+		ctx.Request.ContentLength = int64(len(buf))
+		ctx.Request.Header.Set("Content-Type", contentType)
+		h.Next(ctx)
+	})
+	return r
+}
+
 // Send executes the current request and returns
 // the response or error.
 func (r *Request) Send() (*gentleman.Response, error) {
-	return r.Request.Send()
+	output := harOutput()
+	if output != "" && !r.recording {
+		r.recording = true
+		r.Request.Use(HARRecorder)
+		r.Request.Context.Set(har.RedactKey, r.redactedHeaders())
+	}
+
+	r.Request.Context.Set(middlewareKey, r.middleware())
+
+	res, err := r.Request.Send()
+	if res != nil {
+		r.sent = res.Context
+	}
+
+	if output != "" {
+		if herr := writeHAR(output); err == nil {
+			err = herr
+		}
+	}
+	return res, err
 }
 
 // Expect creates and returns the request test expectation suite.
@@ -256,6 +310,52 @@ func (r *Request) UseHandler(phase string, fn context.HandlerFunc) *Request {
 	return r
 }
 
+// redactedHeaders returns the header fields whose values are redacted
+// in HTTP exchange dumps and recordings, including the client ones.
+func (r *Request) redactedHeaders() []string {
+	opts := envDebugOptions()
+	if r.Client != nil {
+		opts = r.Client.debugOptions()
+	}
+	return append(append([]string(nil), opts.redact...), RedactedHeaders...)
+}
+
+// middlewareKey stores the request middleware plugins in the request context.
+const middlewareKey = "$baloo.middleware"
+
+// middleware returns the request middleware plugins preceded
+// by the client and parent clients ones, in execution order.
+func (r *Request) middleware() []plugin.Plugin {
+	stack := append([]plugin.Plugin(nil), r.Request.Middleware.GetStack()...)
+	for cli := r.Client; cli != nil; cli = cli.Parent {
+		stack = append(append([]plugin.Plugin(nil), cli.Client.Middleware.GetStack()...), stack...)
+	}
+	return stack
+}
+
+// Prepare runs the request and before dial middleware phases in a deep copy
+// of the request, returning the final request context without sending it.
+// The request headers, cookies and HTTP client are copied, so middleware
+// changes are not applied to the original request.
+// Useful to perform the request with other clients, such as WebSocket dialers.
+func (r *Request) Prepare() (*context.Context, error) {
+	req := r.Request.Clone()
+	ctx := req.Context
+	ctx.Request = ctx.Request.Clone(ctx.Request.Context())
+	if ctx.Client != nil {
+		client := *ctx.Client
+		ctx.Client = &client
+	}
+
+	for _, phase := range []string{"request", "before dial"} {
+		ctx = req.Middleware.Run(phase, ctx)
+		if ctx.Error != nil {
+			return nil, ctx.Error
+		}
+	}
+	return ctx, nil
+}
+
 // Clone creates a new side-effects free Request based on the current one.
 func (r *Request) Clone() *Request {
 	return &Request{Request: r.Request.Clone()}
@@ -478,6 +478,23 @@ func TestRequestXML(t *testing.T) {
 	st.Expect(t, string(body), `<xmlTest><name><first>foo</first></name></xmlTest>`)
 }
 
+func TestRequestEncode(t *testing.T) {
+	req := NewRequest()
+	req.Encode("application/yaml", map[string]string{"foo": "bar"})
+	req.Request.Middleware.Run("request", req.Request.Context)
+	st.Expect(t, int(req.Request.Context.Request.ContentLength), 9)
+	st.Expect(t, req.Request.Context.Request.Header.Get("Content-Type"), "application/yaml")
+	body, _ := ioutil.ReadAll(req.Request.Context.Request.Body)
+	st.Expect(t, string(body), "foo: bar\n")
+}
+
+func TestRequestEncodeUnsupported(t *testing.T) {
+	req := NewRequest()
+	req.Encode("application/unknown", "foo")
+	ctx := req.Request.Middleware.Run("request", req.Request.Context)
+	st.Reject(t, ctx.Error, nil)
+}
+
 func TestRequestForm(t *testing.T) {
 	reader := bytes.NewReader([]byte("hello world"))
 	fields := map[string]multipart.Values{
@@ -531,7 +548,19 @@ func TestRequestClone(t *testing.T) {
 	req2 := req1.Clone()
 	st.Expect(t, req1 != req2, true)
 	st.Expect(t, req2.Request.Context.GetString("foo"), req1.Request.Context.GetString("foo"))
-	st.Expect(t, len(req2.Request.Middleware.GetStack()), 2)
+	// User-Agent header, request body capture and the request handler
+	st.Expect(t, len(req2.Request.Middleware.GetStack()), 3)
+}
+
+func TestRequestSendTwice(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	req := NewRequest().URL(ts.URL)
+	stack := len(req.Request.Middleware.GetStack())
+	req.Send()
+	req.Send()
+	st.Expect(t, len(req.Request.Middleware.GetStack()), stack)
 }
 
 func BenchmarkSimpleRequestGet(b *testing.B) {
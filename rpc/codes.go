@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Code represents a gRPC status code, shared by the gRPC-Web and Connect protocols.
+type Code int
+
+// Status codes, as defined by gRPC.
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
+
+// codeNames stores the status code names, as used by the Connect protocol.
+var codeNames = []string{
+	"ok",
+	"canceled",
+	"unknown",
+	"invalid_argument",
+	"deadline_exceeded",
+	"not_found",
+	"already_exists",
+	"permission_denied",
+	"resource_exhausted",
+	"failed_precondition",
+	"aborted",
+	"out_of_range",
+	"unimplemented",
+	"internal",
+	"unavailable",
+	"data_loss",
+	"unauthenticated",
+}
+
+// String returns the status code name, such as "not_found".
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "code_" + strconv.Itoa(int(c))
+}
+
+// ParseCode parses the given status code name or number.
+// Unknown status codes are parsed as Unknown.
+func ParseCode(value string) Code {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		return Code(n)
+	}
+	for i, name := range codeNames {
+		if name == value {
+			return Code(i)
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,21 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestCode(t *testing.T) {
+	st.Expect(t, OK.String(), "ok")
+	st.Expect(t, NotFound.String(), "not_found")
+	st.Expect(t, Unauthenticated.String(), "unauthenticated")
+	st.Expect(t, Code(99).String(), "code_99")
+}
+
+func TestParseCode(t *testing.T) {
+	st.Expect(t, ParseCode("not_found"), NotFound)
+	st.Expect(t, ParseCode("5"), NotFound)
+	st.Expect(t, ParseCode(" 0 "), OK)
+	st.Expect(t, ParseCode("foo"), Unknown)
+}
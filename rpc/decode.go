@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+// Result represents a decoded RPC response.
+type Result struct {
+	// Code stores the response status code.
+	Code Code
+	// Message stores the response status message, if present.
+	Message string
+	// Messages stores the decoded response messages.
+	Messages []proto.Message
+	// Trailers stores the response trailers, including the gRPC status fields.
+	Trailers http.Header
+}
+
+// Decode decodes the RPC response of the given method and protocol.
+// The response body is re-filled to be read again.
+func Decode(res *http.Response, method protoreflect.MethodDescriptor, protocol Protocol) (*Result, error) {
+	body, err := httputil.ReadResponseBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	if protocol == GRPCWeb {
+		return decodeGRPCWeb(res, body, method.Output())
+	}
+	return decodeConnect(res, body, method.Output())
+}
+
+// decodeConnect decodes a Connect unary response.
+func decodeConnect(res *http.Response, body []byte, desc protoreflect.MessageDescriptor) (*Result, error) {
+	result := &Result{Trailers: http.Header{}}
+	for key, values := range res.Header {
+		if strings.HasPrefix(key, "Trailer-") {
+			result.Trailers[strings.TrimPrefix(key, "Trailer-")] = values
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var connectErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(body, &connectErr); err != nil {
+			return nil, fmt.Errorf("rpc: invalid Connect error response (status %d): %s", res.StatusCode, err)
+		}
+		result.Code = ParseCode(connectErr.Code)
+		result.Message = connectErr.Message
+		return result, nil
+	}
+
+	msg := dynamicpb.NewMessage(desc)
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return nil, fmt.Errorf("rpc: invalid %s message: %s", desc.FullName(), err)
+	}
+	result.Messages = append(result.Messages, msg)
+	return result, nil
+}
+
+// decodeGRPCWeb decodes a gRPC-Web response, reading the status from
+// the trailers frame or the response headers for trailers-only responses.
+func decodeGRPCWeb(res *http.Response, body []byte, desc protoreflect.MessageDescriptor) (*Result, error) {
+	result := &Result{Trailers: http.Header{}}
+	for _, key := range []string{"Grpc-Status", "Grpc-Message"} {
+		if value := res.Header.Get(key); value != "" {
+			result.Trailers.Set(key, value)
+		}
+	}
+
+	for len(body) > 0 {
+		if len(body) < 5 {
+			return nil, fmt.Errorf("rpc: invalid gRPC-Web frame: unexpected end of body")
+		}
+		flags, size := body[0], binary.BigEndian.Uint32(body[1:5])
+		if uint32(len(body)-5) < size {
+			return nil, fmt.Errorf("rpc: invalid gRPC-Web frame: unexpected end of body")
+		}
+		data := body[5 : 5+size]
+		body = body[5+size:]
+
+		if flags&0x80 != 0 {
+			// Trailers are encoded as HTTP/1 headers, with an optional final line break
+			block := strings.TrimRight(string(data), "\r\n") + "\r\n\r\n"
+			reader := textproto.NewReader(bufio.NewReader(strings.NewReader(block)))
+			trailers, err := reader.ReadMIMEHeader()
+			if err != nil {
+				return nil, fmt.Errorf("rpc: invalid gRPC-Web trailers: %s", err)
+			}
+			for key, values := range trailers {
+				result.Trailers[key] = values
+			}
+			continue
+		}
+		if flags&0x01 != 0 {
+			return nil, fmt.Errorf("rpc: compressed gRPC-Web messages are not supported")
+		}
+
+		msg := dynamicpb.NewMessage(desc)
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return nil, fmt.Errorf("rpc: invalid %s message: %s", desc.FullName(), err)
+		}
+		result.Messages = append(result.Messages, msg)
+	}
+
+	status := result.Trailers.Get("Grpc-Status")
+	if status == "" {
+		return nil, fmt.Errorf("rpc: missing grpc-status in gRPC-Web response (status %d)", res.StatusCode)
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return nil, fmt.Errorf("rpc: invalid grpc-status: %s", status)
+	}
+	result.Code = Code(code)
+	// Status messages are percent-encoded
+	result.Message = result.Trailers.Get("Grpc-Message")
+	if message, err := url.PathUnescape(result.Message); err == nil {
+		result.Message = message
+	}
+	return result, nil
+}
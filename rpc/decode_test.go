@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func newRPCResponse(status int, header http.Header, body []byte) *http.Response {
+	return &http.Response{StatusCode: status, Header: header, Body: ioutil.NopCloser(bytes.NewReader(body))}
+}
+
+func TestDecodeGRPCWeb(t *testing.T) {
+	method, _ := loadTestDescriptors(t).Method("acme.user.v1.UserService/GetUser")
+
+	// Trailers-only response
+	res := newRPCResponse(200, http.Header{"Grpc-Status": []string{"16"}, "Grpc-Message": []string{"invalid%20token"}}, nil)
+	result, err := Decode(res, method, GRPCWeb)
+	st.Expect(t, err, nil)
+	st.Expect(t, result.Code, Unauthenticated)
+	st.Expect(t, result.Message, "invalid token")
+
+	res = newRPCResponse(200, http.Header{}, append(frame(0, []byte{0x0a, 0x01, '1'}), frame(0x80, []byte("grpc-status: 0"))...))
+	result, err = Decode(res, method, GRPCWeb)
+	st.Expect(t, err, nil)
+	st.Expect(t, result.Code, OK)
+	st.Expect(t, len(result.Messages), 1)
+
+	// Body is re-filled
+	body, _ := ioutil.ReadAll(res.Body)
+	st.Expect(t, len(body), 27)
+}
+
+func TestDecodeGRPCWebInvalid(t *testing.T) {
+	method, _ := loadTestDescriptors(t).Method("acme.user.v1.UserService/GetUser")
+
+	cases := map[string][]byte{
+		"rpc: invalid gRPC-Web frame: unexpected end of body":        {0, 0, 0},
+		"rpc: compressed gRPC-Web messages are not supported":        frame(1, []byte{1}),
+		"rpc: missing grpc-status in gRPC-Web response (status 200)": frame(0, nil),
+		"rpc: invalid grpc-status: foo":                              frame(0x80, []byte("grpc-status: foo")),
+	}
+	for msg, body := range cases {
+		_, err := Decode(newRPCResponse(200, http.Header{}, body), method, GRPCWeb)
+		st.Expect(t, err.Error(), msg)
+	}
+}
+
+func TestDecodeConnect(t *testing.T) {
+	method, _ := loadTestDescriptors(t).Method("acme.user.v1.UserService/GetUser")
+
+	res := newRPCResponse(200, http.Header{"Trailer-Foo": []string{"bar"}}, []byte{0x0a, 0x01, '1'})
+	result, err := Decode(res, method, Connect)
+	st.Expect(t, err, nil)
+	st.Expect(t, result.Code, OK)
+	st.Expect(t, result.Trailers.Get("Foo"), "bar")
+	st.Expect(t, len(result.Messages), 1)
+
+	res = newRPCResponse(403, http.Header{}, []byte(`{"code": "permission_denied", "message": "forbidden"}`))
+	result, err = Decode(res, method, Connect)
+	st.Expect(t, err, nil)
+	st.Expect(t, result.Code, PermissionDenied)
+	st.Expect(t, result.Message, "forbidden")
+
+	_, err = Decode(newRPCResponse(502, http.Header{}, []byte("Bad Gateway")), method, Connect)
+	st.Reject(t, err, nil)
+	_, err = Decode(newRPCResponse(200, http.Header{}, []byte{0xff}), method, Connect)
+	st.Reject(t, err, nil)
+}
@@ -0,0 +1,112 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Descriptors stores the protobuf file descriptors
+// used to encode and decode the RPC messages.
+type Descriptors struct {
+	// Files stores the registered protobuf file descriptors.
+	Files *protoregistry.Files
+}
+
+// NewDescriptors creates a new descriptors registry with the given file descriptors,
+// such as the ones of the generated Go protobuf packages.
+func NewDescriptors(files ...protoreflect.FileDescriptor) (*Descriptors, error) {
+	d := &Descriptors{Files: &protoregistry.Files{}}
+	for _, file := range files {
+		if err := d.register(file); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// LoadProto parses and compiles the given .proto files,
+// resolving imports relative to the given import paths.
+// Well-known types, such as google/protobuf/timestamp.proto, are always available.
+func LoadProto(importPaths []string, files ...string) (*Descriptors, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+	}
+	compiled, err := compiler.Compile(context.Background(), files...)
+	if err != nil {
+		return nil, err
+	}
+
+	fds := make([]protoreflect.FileDescriptor, len(compiled))
+	for i, file := range compiled {
+		fds[i] = file
+	}
+	return NewDescriptors(fds...)
+}
+
+// LoadDescriptorSet reads the binary protobuf descriptor set in the given path,
+// as generated by `protoc --include_imports --descriptor_set_out` or `buf build`.
+func LoadDescriptorSet(path string) (*Descriptors, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(buf, set); err != nil {
+		return nil, fmt.Errorf("rpc: invalid descriptor set %s: %s", path, err)
+	}
+
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	return &Descriptors{Files: files}, nil
+}
+
+// Method returns the descriptor of the given fully-qualified method name,
+// such as "acme.user.v1.UserService/GetUser".
+func (d *Descriptors) Method(name string) (protoreflect.MethodDescriptor, error) {
+	name = strings.TrimPrefix(name, "/")
+	i := strings.LastIndexAny(name, "/.")
+	if i < 0 {
+		return nil, fmt.Errorf("rpc: invalid method name: %s", name)
+	}
+
+	desc, err := d.Files.FindDescriptorByName(protoreflect.FullName(name[:i]))
+	if err != nil {
+		return nil, fmt.Errorf("rpc: service not found: %s", name[:i])
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("rpc: %s is not a service", name[:i])
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(name[i+1:]))
+	if method == nil {
+		return nil, fmt.Errorf("rpc: method not found: %s", name)
+	}
+	return method, nil
+}
+
+// register registers the given file descriptor and its imports.
+func (d *Descriptors) register(file protoreflect.FileDescriptor) error {
+	if _, err := d.Files.FindFileByPath(file.Path()); err == nil {
+		return nil
+	}
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if err := d.register(imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+	return d.Files.RegisterFile(file)
+}
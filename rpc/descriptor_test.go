@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nbio/st"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func loadTestDescriptors(t *testing.T) *Descriptors {
+	desc, err := LoadProto([]string{"testdata"}, "user.proto")
+	st.Assert(t, err, nil)
+	return desc
+}
+
+func TestLoadProto(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	method, err := desc.Method("acme.user.v1.UserService/GetUser")
+	st.Expect(t, err, nil)
+	st.Expect(t, string(method.Input().FullName()), "acme.user.v1.GetUserRequest")
+	st.Expect(t, string(method.Output().FullName()), "acme.user.v1.User")
+
+	_, err = LoadProto([]string{"testdata"}, "missing.proto")
+	st.Reject(t, err, nil)
+}
+
+func TestLoadDescriptorSet(t *testing.T) {
+	method, _ := loadTestDescriptors(t).Method("acme.user.v1.UserService/GetUser")
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+		protodesc.ToFileDescriptorProto(method.ParentFile()),
+	}}
+	buf, err := proto.Marshal(set)
+	st.Assert(t, err, nil)
+
+	dir, _ := ioutil.TempDir("", "baloo")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "user.pb")
+	st.Assert(t, ioutil.WriteFile(path, buf, 0644), nil)
+
+	desc, err := LoadDescriptorSet(path)
+	st.Expect(t, err, nil)
+	method, err = desc.Method("/acme.user.v1.UserService.ListUsers")
+	st.Expect(t, err, nil)
+	st.Expect(t, method.IsStreamingServer(), true)
+
+	ioutil.WriteFile(path, []byte("foo"), 0644)
+	_, err = LoadDescriptorSet(path)
+	st.Reject(t, err, nil)
+}
+
+func TestDescriptorsMethod(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	_, err := desc.Method("acme.user.v1.UserService/Missing")
+	st.Expect(t, err.Error(), "rpc: method not found: acme.user.v1.UserService/Missing")
+	_, err = desc.Method("acme.user.v1.Missing/GetUser")
+	st.Expect(t, err.Error(), "rpc: service not found: acme.user.v1.Missing")
+	_, err = desc.Method("acme.user.v1.User/GetUser")
+	st.Expect(t, err.Error(), "rpc: acme.user.v1.User is not a service")
+	_, err = desc.Method("GetUser")
+	st.Expect(t, err.Error(), "rpc: invalid method name: GetUser")
+}
+
+func TestNewDescriptors(t *testing.T) {
+	desc, err := NewDescriptors(timestamppb.File_google_protobuf_timestamp_proto)
+	st.Expect(t, err, nil)
+	_, err = desc.Files.FindDescriptorByName("google.protobuf.Timestamp")
+	st.Expect(t, err, nil)
+}
@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/assert"
+)
+
+// Expect represents the RPC call expectation suite,
+// able to assert the decoded response status and messages.
+// The common HTTP assertions, such as Status or Header, return the RPC
+// expectation suite so they can be chained with the RPC assertions.
+// Other assertions of the embedded baloo.Expect return the HTTP expectation
+// suite, so they must be the last ones in the chain or be called separately.
+type Expect struct {
+	// Expect stores the HTTP expectation suite of the call.
+	*baloo.Expect
+
+	call *Call
+}
+
+// Code asserts the response status code.
+func (e *Expect) Code(code Code) *Expect {
+	e.assert(func(result *Result) error {
+		if result.Code != code {
+			return fmt.Errorf("RPC status mismatch: %s != %s", result.Code, code)
+		}
+		return nil
+	})
+	return e
+}
+
+// StatusMessage asserts the response status message, such as the grpc-message trailer.
+func (e *Expect) StatusMessage(message string) *Expect {
+	e.assert(func(result *Result) error {
+		if result.Message != message {
+			return fmt.Errorf("RPC status message mismatch: '%s' != '%s'", result.Message, message)
+		}
+		return nil
+	})
+	return e
+}
+
+// Trailer asserts a response trailer field value,
+// such as grpc-status, grpc-message or custom trailers.
+func (e *Expect) Trailer(key, value string) *Expect {
+	e.assert(func(result *Result) error {
+		if actual := result.Trailers.Get(key); actual != value {
+			return fmt.Errorf("RPC trailer '%s' mismatch: '%s' != '%s'", key, actual, value)
+		}
+		return nil
+	})
+	return e
+}
+
+// Message asserts the first response message with the given JSON structure,
+// compared with the message JSON representation.
+func (e *Expect) Message(data interface{}) *Expect {
+	return e.MessageAt(0, data)
+}
+
+// MessageAt asserts the response message at the given index with the given JSON structure,
+// compared with the message JSON representation.
+func (e *Expect) MessageAt(index int, data interface{}) *Expect {
+	e.assert(func(result *Result) error {
+		if index >= len(result.Messages) {
+			return fmt.Errorf("RPC message #%d not found: received %d messages", index, len(result.Messages))
+		}
+		buf, err := protojson.Marshal(result.Messages[index])
+		if err != nil {
+			return err
+		}
+		res := &http.Response{Body: ioutil.NopCloser(bytes.NewReader(buf))}
+		if err := assert.JSON(data)(res, nil); err != nil {
+			return fmt.Errorf("RPC message #%d mismatch: %s", index, err)
+		}
+		return nil
+	})
+	return e
+}
+
+// MessageCount asserts the number of response messages.
+func (e *Expect) MessageCount(count int) *Expect {
+	e.assert(func(result *Result) error {
+		if len(result.Messages) != count {
+			return fmt.Errorf("RPC message count mismatch: %d != %d", len(result.Messages), count)
+		}
+		return nil
+	})
+	return e
+}
+
+// Status asserts the response HTTP status code.
+func (e *Expect) Status(code int) *Expect {
+	e.Expect.Status(code)
+	return e
+}
+
+// StatusOk asserts the response HTTP status code
+// as valid response (>= 200 && < 400).
+func (e *Expect) StatusOk() *Expect {
+	e.Expect.StatusOk()
+	return e
+}
+
+// StatusError asserts the response HTTP status code
+// as client/server error response (>= 400 && < 600).
+func (e *Expect) StatusError() *Expect {
+	e.Expect.StatusError()
+	return e
+}
+
+// Type asserts the response MIME type.
+func (e *Expect) Type(kind string) *Expect {
+	e.Expect.Type(kind)
+	return e
+}
+
+// Header asserts a response header field value matches the given pattern.
+func (e *Expect) Header(key, value string) *Expect {
+	e.Expect.Header(key, value)
+	return e
+}
+
+// HeaderEquals asserts a response header field value is equal to the given value.
+func (e *Expect) HeaderEquals(key, value string) *Expect {
+	e.Expect.HeaderEquals(key, value)
+	return e
+}
+
+// HeaderNotEquals asserts a response header field value is not equal to the given value.
+func (e *Expect) HeaderNotEquals(key, value string) *Expect {
+	e.Expect.HeaderNotEquals(key, value)
+	return e
+}
+
+// HeaderPresent asserts a response header field is present.
+func (e *Expect) HeaderPresent(key string) *Expect {
+	e.Expect.HeaderPresent(key)
+	return e
+}
+
+// HeaderNotPresent asserts a response header field is not present.
+func (e *Expect) HeaderNotPresent(key string) *Expect {
+	e.Expect.HeaderNotPresent(key)
+	return e
+}
+
+// AssertFunc adds a new assertion function.
+func (e *Expect) AssertFunc(assertion ...assert.Func) *Expect {
+	e.Expect.AssertFunc(assertion...)
+	return e
+}
+
+// Not negates the next assertion.
+func (e *Expect) Not() *Expect {
+	e.Expect.Not()
+	return e
+}
+
+// assert adds a new assertion function of the decoded response.
+func (e *Expect) assert(fn func(*Result) error) {
+	e.AssertFunc(func(res *http.Response, req *http.Request) error {
+		result, err := Decode(res, e.call.method, e.call.protocol)
+		if err != nil {
+			return err
+		}
+		return fn(result)
+	})
+}
@@ -0,0 +1,186 @@
+// Package rpc implements gRPC-Web and Connect protocol support for baloo,
+// encoding protobuf request messages and asserting the decoded responses.
+package rpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/gentleman.v2"
+	"gopkg.in/h2non/gentleman.v2/context"
+)
+
+// Protocol represents the RPC over HTTP protocol.
+type Protocol int
+
+const (
+	// Connect represents the Connect protocol, using unary requests with the proto codec.
+	Connect Protocol = iota
+	// GRPCWeb represents the gRPC-Web protocol, using binary framed messages.
+	GRPCWeb
+)
+
+// String returns the protocol name.
+func (p Protocol) String() string {
+	if p == GRPCWeb {
+		return "grpc-web"
+	}
+	return "connect"
+}
+
+// contentType returns the request content type of the protocol.
+func (p Protocol) contentType() string {
+	if p == GRPCWeb {
+		return "application/grpc-web+proto"
+	}
+	return "application/proto"
+}
+
+// Client represents the RPC client, performing calls via the given baloo client.
+type Client struct {
+	// Client stores the baloo client used to perform the calls.
+	Client *baloo.Client
+
+	// Descriptors stores the protobuf descriptors used to encode and decode messages.
+	Descriptors *Descriptors
+
+	protocol Protocol
+}
+
+// New creates a new RPC client using the given baloo client and protobuf descriptors.
+// The Connect protocol is used by default.
+func New(cli *baloo.Client, descriptors *Descriptors) *Client {
+	return &Client{Client: cli, Descriptors: descriptors}
+}
+
+// Protocol defines the protocol used by the client calls.
+func (c *Client) Protocol(protocol Protocol) *Client {
+	c.protocol = protocol
+	return c
+}
+
+// Call creates a new call of the given fully-qualified method name,
+// such as "acme.user.v1.UserService/GetUser" or "acme.user.v1.UserService.GetUser".
+// The request path is always "/<service>/<method>".
+func (c *Client) Call(method string) *Call {
+	call := &Call{protocol: c.protocol}
+	call.method, call.err = c.Descriptors.Method(method)
+	path := "/" + strings.TrimPrefix(method, "/")
+	if call.err == nil {
+		path = "/" + string(call.method.Parent().FullName()) + "/" + string(call.method.Name())
+	}
+	call.Request = c.Client.Post(path)
+	call.Request.UseRequest(call.encode)
+	return call
+}
+
+// Call represents a RPC call, sending a single request message.
+type Call struct {
+	// Request stores the HTTP request used to perform the call.
+	Request *baloo.Request
+
+	err      error
+	method   protoreflect.MethodDescriptor
+	protocol Protocol
+	message  proto.Message
+}
+
+// Method returns the descriptor of the called method.
+func (c *Call) Method() protoreflect.MethodDescriptor {
+	return c.method
+}
+
+// Message defines the request message.
+func (c *Call) Message(msg proto.Message) *Call {
+	c.message = msg
+	return c
+}
+
+// JSON defines the request message based on its JSON representation,
+// given as string, []byte or any value serializable as JSON.
+func (c *Call) JSON(data interface{}) *Call {
+	if c.err != nil {
+		return c
+	}
+
+	var buf []byte
+	switch data := data.(type) {
+	case string:
+		buf = []byte(data)
+	case []byte:
+		buf = data
+	default:
+		if buf, c.err = json.Marshal(data); c.err != nil {
+			return c
+		}
+	}
+
+	msg := dynamicpb.NewMessage(c.method.Input())
+	if err := protojson.Unmarshal(buf, msg); err != nil {
+		c.err = fmt.Errorf("rpc: invalid %s message: %s", c.method.Input().FullName(), err)
+		return c
+	}
+	return c.Message(msg)
+}
+
+// SetHeader sets a new request header field by name and value.
+func (c *Call) SetHeader(name, value string) *Call {
+	c.Request.SetHeader(name, value)
+	return c
+}
+
+// Send performs the call and returns the response or error.
+func (c *Call) Send() (*gentleman.Response, error) {
+	return c.Request.Send()
+}
+
+// Expect creates and returns the call expectation suite.
+func (c *Call) Expect(t baloo.TestingT) *Expect {
+	return &Expect{Expect: c.Request.Expect(t), call: c}
+}
+
+// encode encodes the request message based on the call protocol.
+func (c *Call) encode(ctx *context.Context, h context.Handler) {
+	if c.err != nil {
+		h.Error(ctx, c.err)
+		return
+	}
+
+	msg := c.message
+	if msg == nil {
+		msg = dynamicpb.NewMessage(c.method.Input())
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+
+	if c.protocol == GRPCWeb {
+		data = frame(0, data)
+		ctx.Request.Header.Set("X-Grpc-Web", "1")
+	} else {
+		ctx.Request.Header.Set("Connect-Protocol-Version", "1")
+	}
+	ctx.Request.Header.Set("Content-Type", c.protocol.contentType())
+	ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(data))
+	ctx.Request.ContentLength = int64(len(data))
+	h.Next(ctx)
+}
+
+// frame returns the given data prefixed by the gRPC message frame header.
+func frame(flags byte, data []byte) []byte {
+	buf := make([]byte, 5, 5+len(data))
+	buf[0] = flags
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(data)))
+	return append(buf, data...)
+}
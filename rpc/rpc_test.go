@@ -0,0 +1,203 @@
+package rpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+// createRPCServer creates a server implementing the UserService
+// over the Connect and gRPC-Web protocols.
+func createRPCServer(t *testing.T, desc *Descriptors) *httptest.Server {
+	getUser, _ := desc.Method("acme.user.v1.UserService/GetUser")
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		grpcWeb := r.Header.Get("Content-Type") == "application/grpc-web+proto"
+		if grpcWeb {
+			body = body[5:]
+		} else if r.Header.Get("Connect-Protocol-Version") != "1" {
+			w.WriteHeader(415)
+			return
+		}
+
+		req := dynamicpb.NewMessage(getUser.Input())
+		st.Assert(t, proto.Unmarshal(body, req), nil)
+		id := req.Get(getUser.Input().Fields().ByName("id")).String()
+
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		w.Header().Set("X-Path", r.URL.Path)
+		if id == "0" {
+			if grpcWeb {
+				w.Header().Set("Grpc-Status", "5")
+				w.Header().Set("Grpc-Message", "user%200%20not%20found")
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(404)
+			w.Write([]byte(`{"code": "not_found", "message": "user 0 not found"}`))
+			return
+		}
+
+		if !grpcWeb {
+			w.Header().Set("Trailer-X-Request-Id", "123")
+		}
+		users := []string{id}
+		if strings.HasSuffix(r.URL.Path, "/ListUsers") {
+			users = append(users, id+"-2")
+		}
+		for _, id := range users {
+			user := dynamicpb.NewMessage(getUser.Output())
+			protojson.Unmarshal([]byte(`{"id": "`+id+`", "name": "foo", "roles": ["admin"], "createdAt": "2020-01-01T00:00:00Z"}`), user)
+			buf, _ := proto.Marshal(user)
+			if grpcWeb {
+				buf = frame(0, buf)
+			}
+			w.Write(buf)
+		}
+
+		if grpcWeb {
+			w.Write(frame(0x80, []byte("grpc-status: 0\r\ngrpc-message: \r\nx-request-id: 123\r\n")))
+		}
+	}))
+}
+
+func TestConnectCall(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	cli := New(baloo.New(ts.URL), desc)
+	cli.Call("acme.user.v1.UserService/GetUser").
+		JSON(map[string]string{"id": "1"}).
+		Expect(t).
+		Code(OK).
+		Trailer("X-Request-Id", "123").
+		MessageCount(1).
+		Message(`{"id": "1", "name": "foo", "roles": ["admin"], "createdAt": "2020-01-01T00:00:00Z"}`).
+		Status(200).
+		Type("application/proto").
+		Done()
+}
+
+func TestConnectCallMethodName(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	// The HTTP assertions can be chained with the RPC assertions
+	New(baloo.New(ts.URL), desc).
+		Call("acme.user.v1.UserService.GetUser").
+		JSON(`{"id": "1"}`).
+		Expect(t).
+		Status(200).
+		HeaderEquals("X-Path", "/acme.user.v1.UserService/GetUser").
+		Code(OK).
+		MessageCount(1).
+		Done()
+}
+
+func TestConnectCallError(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	New(baloo.New(ts.URL), desc).
+		Call("acme.user.v1.UserService/GetUser").
+		JSON(`{"id": "0"}`).
+		Expect(t).
+		Code(NotFound).
+		StatusMessage("user 0 not found").
+		MessageCount(0).
+		Status(404).
+		Done()
+}
+
+func TestGRPCWebCall(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	cli := New(baloo.New(ts.URL), desc).Protocol(GRPCWeb)
+	getUser, _ := desc.Method("acme.user.v1.UserService/GetUser")
+	req := dynamicpb.NewMessage(getUser.Input())
+	req.Set(getUser.Input().Fields().ByName("id"), protoreflect.ValueOfString("1"))
+
+	cli.Call("acme.user.v1.UserService/ListUsers").
+		Message(req).
+		Expect(t).
+		Code(OK).
+		Trailer("Grpc-Status", "0").
+		Trailer("X-Request-Id", "123").
+		MessageCount(2).
+		MessageAt(0, map[string]interface{}{"id": "1", "name": "foo", "roles": []string{"admin"}, "createdAt": "2020-01-01T00:00:00Z"}).
+		MessageAt(1, `{"id": "1-2", "name": "foo", "roles": ["admin"], "createdAt": "2020-01-01T00:00:00Z"}`).
+		Done()
+}
+
+func TestGRPCWebCallError(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	New(baloo.New(ts.URL), desc).Protocol(GRPCWeb).
+		Call("acme.user.v1.UserService/GetUser").
+		JSON(`{"id": "0"}`).
+		Expect(t).
+		Code(NotFound).
+		StatusMessage("user 0 not found").
+		Status(200).
+		Done()
+}
+
+func TestCallInvalid(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	cli := New(baloo.New(ts.URL), desc)
+	_, err := cli.Call("acme.user.v1.UserService/Missing").Send()
+	st.Expect(t, err.Error(), "rpc: method not found: acme.user.v1.UserService/Missing")
+
+	_, err = cli.Call("acme.user.v1.UserService/GetUser").JSON(`{"foo": 1}`).Send()
+	st.Expect(t, strings.HasPrefix(err.Error(), "rpc: invalid acme.user.v1.GetUserRequest message"), true)
+}
+
+type testingMock struct {
+	failed bool
+}
+
+func (m *testingMock) Error(args ...interface{})               { m.failed = true }
+func (m *testingMock) Fail()                                   { m.failed = true }
+func (m *testingMock) Logf(format string, args ...interface{}) {}
+
+func TestCallMismatch(t *testing.T) {
+	desc := loadTestDescriptors(t)
+	ts := createRPCServer(t, desc)
+	defer ts.Close()
+
+	cli := New(baloo.New(ts.URL), desc)
+	for _, fn := range []func(*Expect){
+		func(e *Expect) { e.Code(NotFound) },
+		func(e *Expect) { e.StatusMessage("foo") },
+		func(e *Expect) { e.Trailer("X-Request-Id", "foo") },
+		func(e *Expect) { e.MessageCount(2) },
+		func(e *Expect) { e.MessageAt(1, `{}`) },
+		func(e *Expect) { e.Message(`{"id": "2"}`) },
+	} {
+		mock := &testingMock{}
+		exp := cli.Call("acme.user.v1.UserService/GetUser").JSON(`{"id": "1"}`).Expect(mock)
+		fn(exp)
+		exp.Done()
+		st.Expect(t, mock.failed, true)
+	}
+}
@@ -0,0 +1,85 @@
+package sign
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+var awsTestTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+func TestSignAWSV4(t *testing.T) {
+	// Example from AWS Signature Version 4 documentation
+	req, _ := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	err := SignAWSV4(AWSConfig{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "iam",
+		Now:             func() time.Time { return awsTestTime },
+	}, req, nil)
+
+	st.Expect(t, err, nil)
+	st.Expect(t, req.Header.Get("X-Amz-Date"), "20150830T123600Z")
+	st.Expect(t, req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 "+
+		"Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7")
+}
+
+func TestSignAWSV4Errors(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://foo.com", nil)
+	st.Reject(t, SignAWSV4(AWSConfig{Region: "us-east-1", Service: "s3"}, req, nil), nil)
+	st.Reject(t, SignAWSV4(AWSConfig{AccessKeyID: "id", SecretAccessKey: "secret"}, req, nil), nil)
+}
+
+func TestAWSV4Plugin(t *testing.T) {
+	config := AWSConfig{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Region:          "us-east-1",
+		Service:         "s3",
+		Now:             func() time.Time { return awsTestTime },
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := httputil.ReadRequestBody(r)
+		received := r.Header.Get("Authorization")
+
+		// Verify the signature by signing the received request again
+		r.URL.Host = r.Host
+		if err := SignAWSV4(config, r, body); err != nil || r.Header.Get("Authorization") != received {
+			w.WriteHeader(403)
+		}
+		w.Write([]byte(received))
+	}))
+	defer ts.Close()
+
+	res, err := baloo.New(ts.URL).Use(AWSV4(config)).
+		Put("/bucket/my file.txt").
+		AddQuery("x-id", "PutObject").
+		BodyString("hello").
+		// Request middleware runs before signing
+		SetHeader("X-Amz-Meta-Foo", "bar").
+		Send()
+
+	st.Expect(t, err, nil)
+	st.Expect(t, res.StatusCode, 200)
+	st.Expect(t, strings.Contains(res.String(), "/20150830/us-east-1/s3/aws4_request"), true)
+	st.Expect(t, strings.Contains(res.String(), "SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-meta-foo;x-amz-security-token"), true)
+	st.Expect(t, res.RawRequest.Header.Get("X-Amz-Content-Sha256"), "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+}
+
+func TestAWSEscape(t *testing.T) {
+	st.Expect(t, awsEscape("/foo bar/ü~", false), "/foo%20bar/%C3%BC~")
+	st.Expect(t, awsEscape("a/b+c=", true), "a%2Fb%2Bc%3D")
+}
@@ -0,0 +1,143 @@
+package sign
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// HMACConfig represents the HMAC request signing configuration.
+type HMACConfig struct {
+	// KeyID stores the key identifier sent in the signature header.
+	KeyID string
+	// Secret stores the HMAC secret key.
+	Secret []byte
+	// Hash stores the hash function. Defaults to SHA-256.
+	Hash func() hash.Hash
+	// Scheme stores the signature header scheme. Defaults to "HMAC-SHA256".
+	Scheme string
+	// Header stores the header field name used to send the signature.
+	// Defaults to Authorization.
+	Header string
+	// Headers stores the header fields included in the signature.
+	// The Host header field is always included. If the Date header field
+	// is included but not present, it is set to the current time.
+	Headers []string
+	// Now returns the current time used by the Date header. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// HMAC creates a new plugin signing the outgoing requests with HMAC.
+// The signature header has the following format:
+//
+//	HMAC-SHA256 keyId="id", headers="date host", signature="base64"
+//
+// See HMACStringToSign for the signed content.
+func HMAC(config HMACConfig) plugin.Plugin {
+	return New(func(req *http.Request, body []byte) error {
+		return SignHMAC(config, req, body)
+	})
+}
+
+// SignHMAC signs the given request and body with HMAC, defining the signature header.
+func SignHMAC(config HMACConfig, req *http.Request, body []byte) error {
+	config = config.defaults()
+	if len(config.Secret) == 0 {
+		return errors.New("sign: missing HMAC secret")
+	}
+
+	for _, name := range config.Headers {
+		if strings.EqualFold(name, "Date") && req.Header.Get("Date") == "" {
+			req.Header.Set("Date", config.Now().UTC().Format(http.TimeFormat))
+		}
+	}
+
+	signature := HMACSignature(config, req, body)
+	req.Header.Set(config.Header, fmt.Sprintf(`%s keyId="%s", headers="%s", signature="%s"`,
+		config.Scheme, config.KeyID, strings.Join(signedHeaders(config.Headers), " "), signature))
+	return nil
+}
+
+// HMACSignature returns the base64 encoded HMAC signature of the given request and body.
+// Useful to verify signatures in stub servers.
+func HMACSignature(config HMACConfig, req *http.Request, body []byte) string {
+	config = config.defaults()
+	mac := hmac.New(config.Hash, config.Secret)
+	mac.Write([]byte(HMACStringToSign(config, req, body)))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// HMACStringToSign returns the content signed with HMAC, composed by the following lines:
+// the request method, the escaped URL path, the sorted URL query, one "name:value"
+// line per signed header in lower case, and the hex encoded hash of the body.
+func HMACStringToSign(config HMACConfig, req *http.Request, body []byte) string {
+	config = config.defaults()
+	lines := []string{
+		strings.ToUpper(req.Method),
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+	}
+	for _, name := range signedHeaders(config.Headers) {
+		lines = append(lines, name+":"+headerValue(req, name))
+	}
+
+	h := config.Hash()
+	h.Write(body)
+	lines = append(lines, hex.EncodeToString(h.Sum(nil)))
+	return strings.Join(lines, "\n")
+}
+
+func (c HMACConfig) defaults() HMACConfig {
+	if c.Hash == nil {
+		c.Hash = sha256.New
+	}
+	if c.Scheme == "" {
+		c.Scheme = "HMAC-SHA256"
+	}
+	if c.Header == "" {
+		c.Header = "Authorization"
+	}
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+	return c
+}
+
+// signedHeaders returns the sorted lower case signed header names, including host.
+func signedHeaders(headers []string) []string {
+	names := []string{"host"}
+	for _, name := range headers {
+		name = strings.ToLower(name)
+		if name != "host" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// headerValue returns the trimmed header value by lower case name,
+// joining multiple values by comma.
+func headerValue(req *http.Request, name string) string {
+	if name == "host" {
+		if req.Host != "" {
+			return req.Host
+		}
+		return req.URL.Host
+	}
+	var values []string
+	for _, value := range req.Header[http.CanonicalHeaderKey(name)] {
+		values = append(values, strings.Join(strings.Fields(value), " "))
+	}
+	return strings.Join(values, ",")
+}
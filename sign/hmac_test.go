@@ -0,0 +1,101 @@
+package sign
+
+import (
+	"crypto/sha1"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+)
+
+var hmacTestTime = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestHMACStringToSign(t *testing.T) {
+	req, _ := http.NewRequest("post", "http://foo.com/users/a%20b?z=1&a=2&a=1", nil)
+	req.Header.Set("X-Foo", "  bar   baz ")
+
+	config := HMACConfig{Secret: []byte("secret"), Headers: []string{"X-Foo"}}
+	st.Expect(t, HMACStringToSign(config, req, []byte("hello")), strings.Join([]string{
+		"POST",
+		"/users/a%20b",
+		"a=1&a=2&z=1",
+		"host:foo.com",
+		"x-foo:bar baz",
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}, "\n"))
+	st.Expect(t, req.Header.Get("X-Foo"), "  bar   baz ")
+}
+
+func TestSignHMAC(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://foo.com/", nil)
+	config := HMACConfig{
+		KeyID:   "key",
+		Secret:  []byte("secret"),
+		Hash:    sha1.New,
+		Scheme:  "HMAC-SHA1",
+		Header:  "Signature",
+		Headers: []string{"Date"},
+		Now:     func() time.Time { return hmacTestTime },
+	}
+
+	st.Expect(t, SignHMAC(config, req, nil), nil)
+	st.Expect(t, req.Header.Get("Date"), "Thu, 02 Jan 2020 03:04:05 GMT")
+	st.Expect(t, req.Header.Get("Signature"), `HMAC-SHA1 keyId="key", headers="date host", signature="`+HMACSignature(config, req, nil)+`"`)
+	st.Reject(t, SignHMAC(HMACConfig{}, req, nil), nil)
+}
+
+func TestHMACPlugin(t *testing.T) {
+	config := HMACConfig{KeyID: "key", Secret: []byte("secret"), Headers: []string{"Content-Type", "Date"}}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := httputil.ReadRequestBody(r)
+		expected := `signature="` + HMACSignature(config, r, body) + `"`
+		if !strings.HasSuffix(r.Header.Get("Authorization"), expected) {
+			w.WriteHeader(401)
+		}
+	}))
+	defer ts.Close()
+
+	test := baloo.New(ts.URL).Use(HMAC(config))
+	test.Post("/users").
+		AddQuery("foo", "bar").
+		JSON(map[string]string{"name": "foo"}).
+		Expect(t).
+		Status(200).
+		Done()
+
+	invalid := config
+	invalid.Secret = []byte("invalid")
+	baloo.New(ts.URL).Use(HMAC(invalid)).
+		Get("/users").
+		Expect(t).
+		Status(401).
+		Done()
+}
+
+func TestPluginTLS(t *testing.T) {
+	config := HMACConfig{KeyID: "key", Secret: []byte("secret")}
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.Header.Get("Authorization"), `signature="`+HMACSignature(config, r, nil)+`"`) {
+			w.WriteHeader(401)
+		}
+	}))
+	defer ts.Close()
+
+	test := baloo.New(ts.URL).
+		TLS(baloo.TLSConfig{InsecureSkipVerify: true}).
+		Use(HMAC(config))
+	for i := 0; i < 2; i++ {
+		res, err := test.Get("/").Send()
+		st.Expect(t, err, nil)
+		st.Expect(t, res.StatusCode, 200)
+		_, ok := res.Context.Client.Transport.(*http.Transport)
+		st.Expect(t, ok, true)
+	}
+}
@@ -0,0 +1,39 @@
+// Package sign implements gentleman plugins to sign the outgoing
+// HTTP requests, such as HMAC and AWS Signature Version 4.
+//
+// Signatures are computed right before the request is sent over the
+// network, in the before dial middleware phase, over the final method,
+// path, query, headers and body. The HTTP client transport is not
+// modified, so TLS settings and WebSocket connections are preserved.
+package sign
+
+import (
+	"net/http"
+
+	"gopkg.in/h2non/baloo.v3/internal/httputil"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// Signer signs the given HTTP request with its body,
+// typically defining the signature header fields.
+type Signer func(req *http.Request, body []byte) error
+
+// New creates a new plugin signing the outgoing requests with the given signer.
+// Requests are signed in the before dial phase, once the request and its body
+// are defined, and the signature header fields are visible in request dumps
+// and curl commands. Retried requests are signed again.
+func New(signer Signer) plugin.Plugin {
+	return plugin.NewPhasePlugin("before dial", func(ctx *context.Context, h context.Handler) {
+		body, err := httputil.ReadRequestBody(ctx.Request)
+		if err != nil {
+			h.Error(ctx, err)
+			return
+		}
+		if err := signer(ctx.Request, body); err != nil {
+			h.Error(ctx, err)
+			return
+		}
+		h.Next(ctx)
+	})
+}
@@ -0,0 +1,133 @@
+package baloo
+
+import (
+	"net/http"
+	"time"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+// DefaultSSETimeout defines the default time to wait for Server-Sent Events.
+var DefaultSSETimeout = 10 * time.Second
+
+// SSE represents the Server-Sent Events expectation suite,
+// able to assert the sequence of events of a text/event-stream response.
+type SSE struct {
+	expect  *Expect
+	max     int
+	count   int
+	timeout time.Duration
+	events  [][]assert.SSEMatcher
+}
+
+// SSE asserts the response as a Server-Sent Events stream,
+// returning the events expectation suite.
+// Events are read incrementally until the stream ends, the max number
+// of events is reached or the timeout expires, whichever happens first.
+// If no max nor count is defined, reading stops once all the expected events are received.
+func (e *Expect) SSE() *SSE {
+	sse := &SSE{expect: e, count: -1, timeout: DefaultSSETimeout}
+	e.AssertFunc(assert.Type("text/event-stream"))
+	e.AssertFunc(sse.assert)
+	return sse
+}
+
+// Timeout defines the maximum time to wait for events.
+// Zero means no timeout.
+func (s *SSE) Timeout(timeout time.Duration) *SSE {
+	s.timeout = timeout
+	return s
+}
+
+// MaxEvents defines the maximum number of events to read from the stream.
+func (s *SSE) MaxEvents(max int) *SSE {
+	s.max = max
+	return s
+}
+
+// Count asserts the number of events received.
+// Unless a max is defined, events are read until the stream ends or the timeout expires.
+func (s *SSE) Count(count int) *SSE {
+	s.count = count
+	return s
+}
+
+// Event asserts the next event in the stream with the given type name.
+// Use "message" for events with no explicit type name.
+func (s *SSE) Event(name string) *SSE {
+	s.events = append(s.events, []assert.SSEMatcher{assert.EventName(name)})
+	return s
+}
+
+// ID asserts the current event ID.
+func (s *SSE) ID(id string) *SSE {
+	return s.match(assert.EventID(id))
+}
+
+// Data asserts the current event data using strict comparison.
+func (s *SSE) Data(data string) *SSE {
+	return s.match(assert.EventData(data))
+}
+
+// JSON asserts the current event data with the given JSON structure.
+func (s *SSE) JSON(data interface{}) *SSE {
+	return s.match(assert.EventJSON(data))
+}
+
+// Retry asserts the current event reconnection time in milliseconds.
+func (s *SSE) Retry(retry int) *SSE {
+	return s.match(assert.EventRetry(retry))
+}
+
+// Match asserts the current event with the given matcher functions.
+func (s *SSE) Match(matchers ...assert.SSEMatcher) *SSE {
+	return s.match(matchers...)
+}
+
+// Expect returns the parent expectation suite.
+func (s *SSE) Expect() *Expect {
+	return s.expect
+}
+
+// Done performs and asserts the HTTP response based
+// on the defined expectations.
+func (s *SSE) Done() error {
+	return s.expect.Done()
+}
+
+// End is an alias to `Done()`.
+func (s *SSE) End() error {
+	return s.expect.Done()
+}
+
+// Send does the same as `Done()`, but it also returns the `*http.Response` along with the `error`.
+func (s *SSE) Send() (*gentleman.Response, error) {
+	return s.expect.Send()
+}
+
+// match adds the given matchers to the current event,
+// which is the next one in the stream if no event was defined yet.
+func (s *SSE) match(matchers ...assert.SSEMatcher) *SSE {
+	if len(s.events) == 0 {
+		s.events = append(s.events, nil)
+	}
+	i := len(s.events) - 1
+	s.events[i] = append(s.events[i], matchers...)
+	return s
+}
+
+func (s *SSE) assert(res *http.Response, req *http.Request) error {
+	max := s.max
+	if max == 0 && s.count < 0 {
+		max = len(s.events)
+	}
+
+	if err := assert.Events(max, s.timeout, s.events...)(res, req); err != nil {
+		return err
+	}
+	if s.count >= 0 {
+		return assert.EventCount(s.count, max, s.timeout)(res, req)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package baloo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+)
+
+// createSSEServer creates a server streaming events forever.
+func createSSEServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "retry: 1000\n\n")
+		for i := 1; ; i++ {
+			_, err := fmt.Fprintf(w, "event: tick\nid: %d\ndata: {\"count\": %d}\n\n", i, i)
+			if err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+}
+
+func TestExpectSSE(t *testing.T) {
+	ts := createSSEServer()
+	defer ts.Close()
+
+	New(ts.URL).Get("/").
+		Expect(t).
+		Status(200).
+		SSE().
+		Event("tick").ID("1").JSON(map[string]int{"count": 1}).Retry(1000).
+		Event("tick").ID("2").Data(`{"count": 2}`).
+		Done()
+}
+
+func TestExpectSSEWithHAR(t *testing.T) {
+	ts := createSSEServer()
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "baloo")
+	st.Expect(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sse.har")
+	SetHAROutput(path)
+	defer SetHAROutput("")
+	defer HARRecorder.Flush()
+
+	// The open stream must not be read by the HAR recorder
+	New(ts.URL).Get("/").
+		Expect(t).
+		Status(200).
+		SSE().
+		Timeout(time.Second).
+		Event("tick").ID("1").
+		Done()
+
+	buf, err := ioutil.ReadFile(path)
+	st.Expect(t, err, nil)
+	st.Expect(t, strings.Contains(string(buf), `"mimeType": "text/event-stream"`), true)
+	st.Expect(t, strings.Contains(string(buf), `"bodySize": -1`), true)
+}
+
+func TestExpectSSEMaxEvents(t *testing.T) {
+	ts := createSSEServer()
+	defer ts.Close()
+
+	New(ts.URL).Get("/").
+		Expect(t).
+		SSE().
+		MaxEvents(5).
+		Count(5).
+		Done()
+}
+
+func TestExpectSSETimeout(t *testing.T) {
+	ts := createSSEServer()
+	defer ts.Close()
+
+	start := time.Now()
+	mock := &testingMock{}
+	New(ts.URL).Get("/").
+		Expect(mock).
+		SSE().
+		Timeout(50 * time.Millisecond).
+		Count(1000).
+		Done()
+
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, time.Since(start) < time.Second, true)
+}
+
+func TestExpectSSEMismatch(t *testing.T) {
+	ts := createSSEServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := New(ts.URL).Get("/").
+		Expect(mock).
+		SSE().
+		Event("tick").
+		Event("tock").
+		Done()
+
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), "SSE event #2 mismatch: event name 'tick' != 'tock'")
+}
+
+func TestExpectSSEType(t *testing.T) {
+	ts := createEchoServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	New(ts.URL).Get("/").Expect(mock).SSE().Done()
+	st.Expect(t, mock.failed, true)
+}
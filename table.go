@@ -0,0 +1,121 @@
+package baloo
+
+import (
+	"io"
+	"testing"
+)
+
+// Case represents a table-driven test case
+// to be executed by Table as a subtest.
+type Case struct {
+	// Name stores the case label used as subtest name.
+	// Defaults to "<method> <path>" if empty.
+	Name string
+
+	// Method stores the HTTP method to use. Defaults to GET.
+	Method string
+
+	// Path stores the request URL path.
+	Path string
+
+	// Params stores the path params to replace in the URL path.
+	Params map[string]string
+
+	// Query stores the URL query params to set.
+	Query map[string]string
+
+	// Headers stores the request header fields to set.
+	Headers map[string]string
+
+	// Body stores the optional request body.
+	// Strings and byte slices are sent as raw body, io.Reader values
+	// are streamed and any other value is serialized as JSON.
+	Body interface{}
+
+	// Status stores the expected response status code, if not zero.
+	Status int
+
+	// Expect stores an optional function to define custom
+	// expectations on the case request.
+	Expect func(*Expect)
+
+	// Parallel runs the case in parallel with other parallel cases.
+	Parallel bool
+}
+
+// Table runs the given table-driven cases as subtests based on the
+// given Client, reusing the Client request configuration.
+// Note that parallel cases are resumed once the calling test function
+// returns, so wrap the call in t.Run() if the tested server is closed
+// by the caller.
+func Table(t *testing.T, cli *Client, cases []Case) {
+	for _, c := range cases {
+		c := c
+		t.Run(c.label(), func(t *testing.T) {
+			if c.Parallel {
+				t.Parallel()
+			}
+			c.expect(c.Request(cli), t).Done()
+		})
+	}
+}
+
+// Request creates a new Request based on the given Client
+// and the current case params.
+func (c Case) Request(cli *Client) *Request {
+	req := cli.Request()
+
+	method := c.Method
+	if method == "" {
+		method = "GET"
+	}
+	req.Method(method)
+	req.Path(c.Path)
+
+	if c.Params != nil {
+		req.Params(c.Params)
+	}
+	if c.Query != nil {
+		req.SetQueryParams(c.Query)
+	}
+	if c.Headers != nil {
+		req.SetHeaders(c.Headers)
+	}
+
+	switch body := c.Body.(type) {
+	case nil:
+	case string:
+		req.BodyString(body)
+	case []byte:
+		req.BodyString(string(body))
+	case io.Reader:
+		req.Body(body)
+	default:
+		req.JSON(body)
+	}
+
+	return req
+}
+
+// expect creates the case test expectation suite for the given request.
+func (c Case) expect(req *Request, t TestingT) *Expect {
+	exp := req.Expect(t)
+	if c.Status != 0 {
+		exp.Status(c.Status)
+	}
+	if c.Expect != nil {
+		c.Expect(exp)
+	}
+	return exp
+}
+
+func (c Case) label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	method := c.Method
+	if method == "" {
+		method = "GET"
+	}
+	return method + " " + c.Path
+}
@@ -0,0 +1,78 @@
+package baloo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+func TestTable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Method", r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(404)
+		}
+		fmt.Fprintf(w, `{"path":%q,"query":%q,"body":%q}`, r.URL.Path, r.URL.RawQuery, string(body))
+	}))
+	defer ts.Close()
+
+	t.Run("group", func(t *testing.T) {
+		Table(t, New(ts.URL), []Case{
+			{
+				Name:   "get user",
+				Path:   "/users/:id",
+				Params: map[string]string{"id": "123"},
+				Query:  map[string]string{"foo": "bar"},
+				Status: 200,
+				Expect: func(e *Expect) {
+					e.Type("json").JSON(map[string]string{"path": "/users/123", "query": "foo=bar", "body": ""})
+				},
+			},
+			{
+				Method:   "POST",
+				Path:     "/users",
+				Body:     "hello",
+				Status:   200,
+				Parallel: true,
+				Expect: func(e *Expect) {
+					e.HeaderEquals("Method", "POST").BodyMatchString(`"body":"hello"`)
+				},
+			},
+			{
+				Name:     "not found",
+				Path:     "/missing",
+				Status:   404,
+				Parallel: true,
+			},
+		})
+	})
+}
+
+func TestCaseRequest(t *testing.T) {
+	c := Case{
+		Method:  "PUT",
+		Path:    "/foo/:bar",
+		Params:  map[string]string{"bar": "baz"},
+		Headers: map[string]string{"Foo": "bar"},
+		Body:    map[string]string{"foo": "bar"},
+	}
+
+	req := c.Request(New("http://foo.com"))
+	req.Request.Middleware.Run("request", req.Request.Context)
+	st.Expect(t, req.Request.Context.Request.Method, "PUT")
+	st.Expect(t, req.Request.Context.Request.URL.String(), "http://foo.com/foo/baz")
+	st.Expect(t, req.Request.Context.Request.Header.Get("Foo"), "bar")
+	st.Expect(t, req.Request.Context.Request.Header.Get("Content-Type"), "application/json")
+}
+
+func TestCaseLabel(t *testing.T) {
+	st.Expect(t, Case{Name: "foo"}.label(), "foo")
+	st.Expect(t, Case{Path: "/foo"}.label(), "GET /foo")
+	st.Expect(t, Case{Method: "POST", Path: "/foo"}.label(), "POST /foo")
+}
@@ -0,0 +1,195 @@
+package baloo
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// TLSConfig represents the TLS configuration used by client requests.
+type TLSConfig struct {
+	// CertFile and KeyFile store the PEM encoded client
+	// certificate and private key file paths, used for mutual TLS.
+	CertFile string
+	KeyFile  string
+	// CAFile stores the PEM encoded CA bundle file path
+	// trusted to verify server certificates.
+	CAFile string
+	// Pins stores the server certificate pins in "sha256/<base64>" format,
+	// as returned by CertificatePin. At least one certificate of the
+	// server chain must match one of the pins.
+	Pins []string
+	// MinVersion stores the minimum TLS version, such as tls.VersionTLS12.
+	MinVersion uint16
+	// InsecureSkipVerify disables the server certificate verification.
+	// Useful for self-signed development servers.
+	InsecureSkipVerify bool
+}
+
+// TLS defines the TLS configuration used by client requests.
+// Requests fail if the certificate files cannot be loaded.
+// The configured HTTP transport, such as one using a proxy or a custom dialer,
+// is cloned and preserved, only replacing its TLS configuration.
+func (c *Client) TLS(config TLSConfig) *Client {
+	return c.Use(newTLSPlugin(config))
+}
+
+// TLSVersion asserts the negotiated TLS protocol version,
+// such as tls.VersionTLS12.
+func (e *Expect) TLSVersion(version uint16) *Expect {
+	e.AssertFunc(assert.TLSVersion(version))
+	return e
+}
+
+// TLSCipherSuite asserts the negotiated TLS cipher suite,
+// such as tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func (e *Expect) TLSCipherSuite(suite uint16) *Expect {
+	e.AssertFunc(assert.TLSCipherSuite(suite))
+	return e
+}
+
+// TLSPeerSubject asserts the server certificate subject matches the given pattern.
+// Regular expressions are supported.
+func (e *Expect) TLSPeerSubject(pattern string) *Expect {
+	e.AssertFunc(assert.TLSPeerSubject(pattern))
+	return e
+}
+
+// TLSPeerSAN asserts the server certificate contains the given subject alternative name.
+func (e *Expect) TLSPeerSAN(name string) *Expect {
+	e.AssertFunc(assert.TLSPeerSAN(name))
+	return e
+}
+
+// TLSPeerValidFor asserts the server certificate does not expire within the given duration.
+func (e *Expect) TLSPeerValidFor(d time.Duration) *Expect {
+	e.AssertFunc(assert.TLSPeerValidFor(d))
+	return e
+}
+
+// CertificatePin returns the pin of the given certificate, as the base64
+// encoded SHA-256 hash of its public key info prefixed by "sha256/".
+func CertificatePin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// tlsPlugin implements the plugin configuring the client TLS transport.
+type tlsPlugin struct {
+	*plugin.Layer
+
+	config TLSConfig
+
+	once   sync.Once
+	err    error
+	tls    *tls.Config
+	mutex  sync.Mutex
+	cloned map[*http.Transport]*http.Transport
+}
+
+func newTLSPlugin(config TLSConfig) *tlsPlugin {
+	p := &tlsPlugin{Layer: plugin.New(), config: config}
+	// Use request phase so transport wrappers defined before dial are preserved
+	p.SetHandler("request", p.request)
+	return p
+}
+
+func (p *tlsPlugin) request(ctx *context.Context, h context.Handler) {
+	p.once.Do(func() {
+		p.tls, p.err = p.config.build()
+	})
+	if p.err != nil {
+		h.Error(ctx, p.err)
+		return
+	}
+
+	transport, err := p.transport(ctx.Client.Transport)
+	if err != nil {
+		h.Error(ctx, err)
+		return
+	}
+	ctx.Client.Transport = transport
+	h.Next(ctx)
+}
+
+// transport returns a clone of the given HTTP transport using the TLS config.
+// Clones are cached per transport so connections are reused across requests.
+func (p *tlsPlugin) transport(base http.RoundTripper) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("cannot configure TLS: unsupported HTTP transport %T", base)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if cloned, ok := p.cloned[transport]; ok {
+		return cloned, nil
+	}
+	if p.cloned == nil {
+		p.cloned = map[*http.Transport]*http.Transport{}
+	}
+	cloned := transport.Clone()
+	cloned.TLSClientConfig = p.tls
+	p.cloned[transport] = cloned
+	return cloned, nil
+}
+
+// build creates the tls.Config loading the certificate files.
+func (c TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         c.MinVersion,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS client certificate: %s", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if c.CAFile != "" {
+		data, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load TLS CA bundle: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("cannot load TLS CA bundle: no PEM certificates found in %s", c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if len(c.Pins) > 0 {
+		pins := c.Pins
+		// VerifyConnection also runs on resumed sessions, unlike VerifyPeerCertificate
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			for _, cert := range state.PeerCertificates {
+				pin := CertificatePin(cert)
+				for _, expected := range pins {
+					if pin == expected {
+						return nil
+					}
+				}
+			}
+			return errors.New("TLS certificate pinning failed: no certificate matches the pins")
+		}
+	}
+
+	return config, nil
+}
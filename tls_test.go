@@ -0,0 +1,251 @@
+package baloo
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nbio/st"
+	"gopkg.in/h2non/gentleman.v2/context"
+	"gopkg.in/h2non/gentleman.v2/plugin"
+)
+
+// tlsHandler replies with the client certificate common name, if any.
+var tlsHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if len(r.TLS.PeerCertificates) > 0 {
+		fmt.Fprint(w, r.TLS.PeerCertificates[0].Subject.CommonName)
+	}
+})
+
+func createTLSServer() *httptest.Server {
+	return httptest.NewTLSServer(tlsHandler)
+}
+
+// writeCA writes the server certificate as PEM CA bundle in the given directory.
+func writeCA(t *testing.T, dir string, ts *httptest.Server) string {
+	path := filepath.Join(dir, "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	st.Expect(t, ioutil.WriteFile(path, data, 0600), nil)
+	return path
+}
+
+// writeClientCert generates a self-signed client certificate and key as PEM files.
+func writeClientCert(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	st.Expect(t, err, nil)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "baloo-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	st.Expect(t, err, nil)
+	cert, _ := x509.ParseCertificate(raw)
+
+	keyRaw, err := x509.MarshalECPrivateKey(key)
+	st.Expect(t, err, nil)
+
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	st.Expect(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: raw}), 0600), nil)
+	st.Expect(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyRaw}), 0600), nil)
+	return certFile, keyFile, cert
+}
+
+func TestClientTLS(t *testing.T) {
+	ts := createTLSServer()
+	defer ts.Close()
+
+	dir, _ := ioutil.TempDir("", "baloo")
+	defer os.RemoveAll(dir)
+
+	New(ts.URL).TLS(TLSConfig{CAFile: writeCA(t, dir, ts), MinVersion: tls.VersionTLS12}).
+		Get("/").
+		Expect(t).
+		Status(200).
+		TLSVersion(tls.VersionTLS13).
+		TLSCipherSuite(tls.TLS_AES_128_GCM_SHA256).
+		TLSPeerSubject("O=Acme Co").
+		TLSPeerSAN("example.com").
+		TLSPeerSAN("127.0.0.1").
+		TLSPeerValidFor(24 * time.Hour).
+		Done()
+
+	// The server certificate is not trusted by default
+	_, err := New(ts.URL).Get("/").Send()
+	st.Reject(t, err, nil)
+}
+
+func TestClientTLSInsecureSkipVerify(t *testing.T) {
+	ts := createTLSServer()
+	defer ts.Close()
+
+	New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true}).
+		Get("/").
+		Expect(t).
+		Status(200).
+		Done()
+}
+
+func TestClientTLSPins(t *testing.T) {
+	ts := createTLSServer()
+	defer ts.Close()
+
+	pin := CertificatePin(ts.Certificate())
+	st.Expect(t, strings.HasPrefix(pin, "sha256/"), true)
+
+	New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true, Pins: []string{"sha256/invalid", pin}}).
+		Get("/").
+		Expect(t).
+		Status(200).
+		Done()
+
+	_, err := New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true, Pins: []string{"sha256/invalid"}}).Get("/").Send()
+	st.Expect(t, strings.Contains(err.Error(), "TLS certificate pinning failed"), true)
+}
+
+func TestClientTLSMinVersion(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+
+	_, err := New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true, MinVersion: tls.VersionTLS13}).Get("/").Send()
+	st.Reject(t, err, nil)
+
+	New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true}).
+		Get("/").
+		Expect(t).
+		TLSVersion(tls.VersionTLS12).
+		Done()
+}
+
+func TestClientTLSClientCertificate(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "baloo")
+	defer os.RemoveAll(dir)
+	certFile, keyFile, cert := writeClientCert(t, dir)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	ts := httptest.NewUnstartedServer(tlsHandler)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.StartTLS()
+	defer ts.Close()
+
+	New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true, CertFile: certFile, KeyFile: keyFile}).
+		Get("/").
+		Expect(t).
+		Status(200).
+		BodyEquals("baloo-client").
+		Done()
+
+	_, err := New(ts.URL).TLS(TLSConfig{InsecureSkipVerify: true}).Get("/").Send()
+	st.Reject(t, err, nil)
+}
+
+func TestClientTLSInvalidFiles(t *testing.T) {
+	_, err := New("https://foo.com").TLS(TLSConfig{CAFile: "missing.pem"}).Get("/").Send()
+	st.Expect(t, strings.Contains(err.Error(), "cannot load TLS CA bundle"), true)
+
+	_, err = New("https://foo.com").TLS(TLSConfig{CertFile: "missing.pem", KeyFile: "missing.key"}).Get("/").Send()
+	st.Expect(t, strings.Contains(err.Error(), "cannot load TLS client certificate"), true)
+}
+
+func TestClientTLSPreservesTransport(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(tlsHandler)
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	var proxied int32
+	transport := &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			atomic.AddInt32(&proxied, 1)
+			return nil, nil
+		},
+	}
+
+	client := New(ts.URL).
+		Use(plugin.NewRequestPlugin(func(ctx *context.Context, h context.Handler) {
+			ctx.Client.Transport = transport
+			h.Next(ctx)
+		})).
+		TLS(TLSConfig{InsecureSkipVerify: true})
+
+	client.Get("/").Expect(t).Status(200).Done()
+	client.Get("/").Expect(t).Status(200).Done()
+
+	// The custom transport settings are preserved and connections are reused
+	st.Expect(t, atomic.LoadInt32(&proxied), int32(2))
+	st.Expect(t, atomic.LoadInt32(&conns), int32(1))
+}
+
+func TestClientTLSUnsupportedTransport(t *testing.T) {
+	_, err := New("https://foo.com").
+		Use(plugin.NewRequestPlugin(func(ctx *context.Context, h context.Handler) {
+			ctx.Client.Transport = roundTripperFunc(http.DefaultTransport.RoundTrip)
+			h.Next(ctx)
+		})).
+		TLS(TLSConfig{InsecureSkipVerify: true}).
+		Get("/").
+		Send()
+	st.Expect(t, strings.Contains(err.Error(), "unsupported HTTP transport"), true)
+}
+
+func TestClientTLSPinsResumedSession(t *testing.T) {
+	ts := createTLSServer()
+	defer ts.Close()
+
+	cache := tls.NewLRUClientSessionCache(1)
+	dial := func(config *tls.Config) error {
+		config.ClientSessionCache = cache
+		conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), config)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		// Read the response so the session ticket is received
+		fmt.Fprint(conn, "GET / HTTP/1.0\r\n\r\n")
+		ioutil.ReadAll(conn)
+		return nil
+	}
+	st.Expect(t, dial(&tls.Config{InsecureSkipVerify: true}), nil)
+
+	// The resumed session must still be verified against the pins
+	config, err := TLSConfig{InsecureSkipVerify: true, Pins: []string{"sha256/invalid"}}.build()
+	st.Expect(t, err, nil)
+	err = dial(config)
+	st.Reject(t, err, nil)
+	st.Expect(t, strings.Contains(err.Error(), "TLS certificate pinning failed"), true)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
@@ -0,0 +1,126 @@
+package baloo
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"gopkg.in/h2non/baloo.v3/assert"
+	"gopkg.in/h2non/gentleman.v2"
+)
+
+// JSONExpect represents a typed expectation suite,
+// which decodes the JSON response body into a value of type T
+// and asserts it with functions over T.
+type JSONExpect[T any] struct {
+	expect *Expect
+	strict bool
+	value  T
+	checks []func(T) error
+}
+
+// ExpectJSON creates a typed expectation suite for the given request,
+// decoding the JSON response body into a value of type T:
+//
+//	baloo.ExpectJSON[User](test.Get("/users/1"), t).
+//	  Field("Name", "foo").
+//	  Where(func(u User) error { ... }).
+//	  Done()
+//
+// Use Expect() to define untyped assertions, such as the response status.
+// The body is decoded once all the untyped assertions pass.
+func ExpectJSON[T any](req *Request, t TestingT) *JSONExpect[T] {
+	e := &JSONExpect[T]{expect: req.Expect(t)}
+	e.expect.binders = append(e.expect.binders, e.assert)
+	return e
+}
+
+// Strict fails if the response body contains fields not defined by T.
+func (e *JSONExpect[T]) Strict() *JSONExpect[T] {
+	e.strict = true
+	return e
+}
+
+// Where asserts the decoded value with the given function.
+func (e *JSONExpect[T]) Where(fn func(T) error) *JSONExpect[T] {
+	e.checks = append(e.checks, fn)
+	return e
+}
+
+// Equal deeply compares the decoded value with the given value,
+// reporting every mismatching field.
+func (e *JSONExpect[T]) Equal(expected T) *JSONExpect[T] {
+	return e.Where(func(value T) error {
+		if lines := diff(value, expected); len(lines) > 0 {
+			return fmt.Errorf("JSON value mismatch:\n\t%s", strings.Join(lines, "\n\t"))
+		}
+		return nil
+	})
+}
+
+// Field deeply compares the field at the given path with the given value.
+// Paths are dot-separated struct field names, JSON field names,
+// map keys or slice indexes, such as "Address.City" or "tags.0".
+// Numbers and strings are converted to the field type.
+func (e *JSONExpect[T]) Field(path string, expected interface{}) *JSONExpect[T] {
+	return e.Where(func(value T) error {
+		field, err := lookupField(reflect.ValueOf(value), path)
+		if err != nil {
+			return err
+		}
+
+		want := convertValue(reflect.ValueOf(expected), field.Type())
+		var lines []string
+		diffValue(path, field, want, &lines)
+		if len(lines) > 0 {
+			return fmt.Errorf("JSON field mismatch:\n\t%s", strings.Join(lines, "\n\t"))
+		}
+		return nil
+	})
+}
+
+// Value returns the decoded value, once the expectation is performed.
+func (e *JSONExpect[T]) Value() T {
+	return e.value
+}
+
+// Expect returns the untyped expectation suite.
+func (e *JSONExpect[T]) Expect() *Expect {
+	return e.expect
+}
+
+// Done performs and asserts the HTTP response based
+// on the defined expectations.
+func (e *JSONExpect[T]) Done() error {
+	return e.expect.Done()
+}
+
+// End is an alias to `Done()`.
+func (e *JSONExpect[T]) End() error {
+	return e.expect.Done()
+}
+
+// Send does the same as `Done()`, but it also returns the `*http.Response` along with the `error`.
+func (e *JSONExpect[T]) Send() (*gentleman.Response, error) {
+	return e.expect.Send()
+}
+
+func (e *JSONExpect[T]) assert(res *http.Response, req *http.Request) error {
+	var value T
+	bind := assert.BindJSON(&value)
+	if e.strict {
+		bind = assert.BindJSONStrict(&value)
+	}
+	if err := bind(res, req); err != nil {
+		return err
+	}
+
+	e.value = value
+	for _, check := range e.checks {
+		if err := check(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package baloo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbio/st"
+)
+
+type typedUser struct {
+	ID      int               `json:"id"`
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags"`
+	Address *typedAddress     `json:"address"`
+	Meta    map[string]string `json:"meta"`
+}
+
+type typedAddress struct {
+	City string `json:"city"`
+}
+
+func typedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1, "name": "foo", "tags": ["a", "b"], "address": {"city": "Madrid"}, "meta": {"role": "admin"}, "extra": true}`)
+	}))
+}
+
+func TestExpectJSONTyped(t *testing.T) {
+	ts := typedServer()
+	defer ts.Close()
+
+	e := ExpectJSON[typedUser](New(ts.URL).Get("/"), t).
+		Field("Name", "foo").
+		Field("id", 1).
+		Field("Address.City", "Madrid").
+		Field("tags.1", "b").
+		Field("Meta.role", "admin").
+		Where(func(u typedUser) error {
+			if len(u.Tags) != 2 {
+				return errors.New("expected two tags")
+			}
+			return nil
+		}).
+		Equal(typedUser{
+			ID:      1,
+			Name:    "foo",
+			Tags:    []string{"a", "b"},
+			Address: &typedAddress{City: "Madrid"},
+			Meta:    map[string]string{"role": "admin"},
+		})
+	e.Expect().Status(200)
+	st.Expect(t, e.Done(), nil)
+	st.Expect(t, e.Value().Address.City, "Madrid")
+}
+
+func TestExpectJSONEqualDiff(t *testing.T) {
+	ts := typedServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).
+		Equal(typedUser{ID: 2, Name: "foo", Tags: []string{"a"}, Address: &typedAddress{City: "Paris"}}).
+		Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), strings.Join([]string{
+		"JSON value mismatch:",
+		"\tID: 1 != 2",
+		"\tTags: length 2 != 1",
+		"\tAddress.City: \"Madrid\" != \"Paris\"",
+		"\tMeta[role]: unexpected \"admin\"",
+	}, "\n"))
+}
+
+func TestExpectJSONField(t *testing.T) {
+	ts := typedServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Field("Address.City", "Paris").Done()
+	st.Expect(t, err.Error(), "JSON field mismatch:\n\tAddress.City: \"Madrid\" != \"Paris\"")
+
+	// Numbers are not truncated to the field type
+	err = ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Field("ID", 1.5).Done()
+	st.Expect(t, err.Error(), "JSON field mismatch:\n\tID: type int != float64")
+
+	err = ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Field("Unknown", 1).Done()
+	st.Expect(t, err.Error(), "field 'Unknown' not found")
+
+	err = ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Field("Address", nil).Done()
+	st.Expect(t, strings.HasPrefix(err.Error(), "JSON field mismatch:\n\tAddress: &baloo.typedAddress"), true)
+}
+
+func TestExpectJSONFieldNil(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1, "address": null}`)
+	}))
+	defer ts.Close()
+
+	ExpectJSON[typedUser](New(ts.URL).Get("/"), t).
+		Field("Address", nil).
+		Field("Tags", nil).
+		Field("Meta", nil).
+		Done()
+}
+
+func TestExpectJSONStrict(t *testing.T) {
+	ts := typedServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Strict().Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), `failed to bind JSON body: json: unknown field "extra"`)
+}
+
+func TestExpectJSONSkipsOnFailure(t *testing.T) {
+	ts := typedServer()
+	defer ts.Close()
+
+	called := false
+	mock := &testingMock{}
+	e := ExpectJSON[typedUser](New(ts.URL).Get("/"), mock).Where(func(typedUser) error {
+		called = true
+		return nil
+	})
+	e.Expect().Status(404)
+	st.Reject(t, e.Done(), nil)
+	st.Expect(t, called, false)
+}
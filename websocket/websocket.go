@@ -0,0 +1,321 @@
+// Package websocket implements WebSocket connection testing based on the
+// baloo client requests, performing the opening handshake with the client
+// headers, cookies, authentication and TLS settings.
+// It is a separate package so the WebSocket dependencies are only
+// required by the tests using it:
+//
+//	import "gopkg.in/h2non/baloo.v3/websocket"
+//
+//	gorilla.New(test, "/chat").Expect(t).SendText("ping").ReceiveText("pong").Done()
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"runtime"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"gopkg.in/h2non/baloo.v3"
+	"gopkg.in/h2non/baloo.v3/assert"
+)
+
+// Message types and close codes, as defined by gorilla/gorilla.
+const (
+	TextMessage   = gorilla.TextMessage
+	BinaryMessage = gorilla.BinaryMessage
+
+	CloseNormalClosure           = gorilla.CloseNormalClosure
+	CloseGoingAway               = gorilla.CloseGoingAway
+	CloseProtocolError           = gorilla.CloseProtocolError
+	CloseUnsupportedData         = gorilla.CloseUnsupportedData
+	CloseInvalidFramePayloadData = gorilla.CloseInvalidFramePayloadData
+	ClosePolicyViolation         = gorilla.ClosePolicyViolation
+	CloseMessageTooBig           = gorilla.CloseMessageTooBig
+	CloseInternalServerErr       = gorilla.CloseInternalServerErr
+)
+
+// DefaultTimeout defines the default time to wait
+// for the WebSocket handshake and received messages.
+var DefaultTimeout = 10 * time.Second
+
+// websocketHeaders stores the handshake headers managed by the WebSocket dialer.
+var websocketHeaders = []string{
+	"Upgrade",
+	"Connection",
+	"Content-Length",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Version",
+	"Sec-Websocket-Extensions",
+}
+
+// Socket represents a WebSocket connection builder.
+// The opening handshake is based on the HTTP request, inheriting
+// the client headers, cookies, authentication and middleware.
+type Socket struct {
+	// Request stores the HTTP request used to perform the opening handshake.
+	Request *baloo.Request
+
+	protocols []string
+	timeout   time.Duration
+}
+
+// New creates a new WebSocket connection builder for the given client path.
+// http and https URLs are dialed as ws and wss respectively.
+func New(client *baloo.Client, path string) *Socket {
+	return &Socket{Request: client.Get(path), timeout: DefaultTimeout}
+}
+
+// SetHeader sets a new handshake header field by name and value.
+func (w *Socket) SetHeader(name, value string) *Socket {
+	w.Request.SetHeader(name, value)
+	return w
+}
+
+// SetQuery sets a new URL query param field.
+func (w *Socket) SetQuery(name, value string) *Socket {
+	w.Request.SetQuery(name, value)
+	return w
+}
+
+// Param replaces a path param based on the given param name and value.
+func (w *Socket) Param(name, value string) *Socket {
+	w.Request.Param(name, value)
+	return w
+}
+
+// Subprotocols defines the WebSocket subprotocols requested by the client.
+func (w *Socket) Subprotocols(protocols ...string) *Socket {
+	w.protocols = append(w.protocols, protocols...)
+	return w
+}
+
+// Timeout defines the maximum time to wait for the opening handshake.
+func (w *Socket) Timeout(timeout time.Duration) *Socket {
+	w.timeout = timeout
+	return w
+}
+
+// Dial performs the opening handshake, returning the WebSocket connection.
+// The handshake response is returned even if the handshake fails.
+func (w *Socket) Dial() (*gorilla.Conn, *http.Response, error) {
+	ctx, err := w.Request.Prepare()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uri := *ctx.Request.URL
+	switch uri.Scheme {
+	case "http":
+		uri.Scheme = "ws"
+	case "https":
+		uri.Scheme = "wss"
+	}
+
+	header := http.Header{}
+	for key, values := range ctx.Request.Header {
+		header[key] = values
+	}
+	for _, key := range websocketHeaders {
+		header.Del(key)
+	}
+
+	dialer := &gorilla.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: w.timeout,
+		Subprotocols:     w.protocols,
+	}
+	if ctx.Client != nil {
+		dialer.Jar = ctx.Client.Jar
+		if transport, ok := ctx.Client.Transport.(*http.Transport); ok {
+			dialer.TLSClientConfig = transport.TLSClientConfig
+			dialer.Proxy = transport.Proxy
+		}
+	}
+
+	return dialer.Dial(uri.String(), header)
+}
+
+// Expect creates and returns the WebSocket test expectation suite.
+func (w *Socket) Expect(t baloo.TestingT) *Expect {
+	return &Expect{test: t, socket: w, timeout: DefaultTimeout}
+}
+
+// Expect represents the WebSocket expectation suite,
+// able to send messages and assert the received ones in order.
+type Expect struct {
+	test    baloo.TestingT
+	socket  *Socket
+	timeout time.Duration
+	closed  bool
+	steps   []func(*gorilla.Conn) error
+}
+
+// Timeout defines the maximum time to wait for each of the following received messages.
+func (e *Expect) Timeout(timeout time.Duration) *Expect {
+	e.timeout = timeout
+	return e
+}
+
+// Protocol asserts the subprotocol negotiated with the server.
+func (e *Expect) Protocol(protocol string) *Expect {
+	return e.step(func(conn *gorilla.Conn) error {
+		if conn.Subprotocol() != protocol {
+			return fmt.Errorf("WebSocket subprotocol mismatch: '%s' != '%s'", conn.Subprotocol(), protocol)
+		}
+		return nil
+	})
+}
+
+// SendText sends a text message.
+func (e *Expect) SendText(text string) *Expect {
+	return e.send(gorilla.TextMessage, []byte(text))
+}
+
+// SendBinary sends a binary message.
+func (e *Expect) SendBinary(data []byte) *Expect {
+	return e.send(gorilla.BinaryMessage, data)
+}
+
+// SendJSON serializes and sends the given data as text message.
+func (e *Expect) SendJSON(data interface{}) *Expect {
+	return e.step(func(conn *gorilla.Conn) error {
+		buf, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		return conn.WriteMessage(gorilla.TextMessage, buf)
+	})
+}
+
+// Receive asserts the next received message with the given matcher functions.
+func (e *Expect) Receive(matchers ...assert.MessageMatcher) *Expect {
+	timeout := e.timeout
+	return e.step(func(conn *gorilla.Conn) error {
+		if timeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(timeout))
+		}
+		kind, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("WebSocket receive error: %s", err)
+		}
+		msg := &assert.Message{Type: kind, Data: data}
+		for _, matcher := range matchers {
+			if err := matcher(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReceiveText asserts the next received message is the given text.
+func (e *Expect) ReceiveText(text string) *Expect {
+	return e.Receive(assert.MessageText(text))
+}
+
+// ReceiveBinary asserts the next received message is the given binary data.
+func (e *Expect) ReceiveBinary(data []byte) *Expect {
+	return e.Receive(assert.MessageBinary(data))
+}
+
+// ReceiveMatch asserts the next received message matches the given regular expression.
+func (e *Expect) ReceiveMatch(pattern string) *Expect {
+	return e.Receive(assert.MessageMatch(pattern))
+}
+
+// ReceiveJSON asserts the next received message contains the given JSON structure.
+// Received objects may have additional fields.
+func (e *Expect) ReceiveJSON(data interface{}) *Expect {
+	return e.Receive(assert.MessageJSON(data))
+}
+
+// Close sends a close message with the given close code and reason.
+func (e *Expect) Close(code int, reason string) *Expect {
+	return e.step(func(conn *gorilla.Conn) error {
+		e.closed = true
+		message := gorilla.FormatCloseMessage(code, reason)
+		return conn.WriteControl(gorilla.CloseMessage, message, time.Now().Add(time.Second))
+	})
+}
+
+// Closed asserts the connection is closed by the server with the given close code.
+// Pending data messages are discarded.
+func (e *Expect) Closed(code int) *Expect {
+	timeout := e.timeout
+	return e.step(func(conn *gorilla.Conn) error {
+		if timeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(timeout))
+		}
+		for {
+			_, _, err := conn.ReadMessage()
+			if err == nil {
+				continue
+			}
+			e.closed = true
+			closeErr, ok := err.(*gorilla.CloseError)
+			if !ok {
+				return fmt.Errorf("WebSocket close mismatch: expected close code %d, got: %s", code, err)
+			}
+			if closeErr.Code != code {
+				return fmt.Errorf("WebSocket close code mismatch: %d != %d", closeErr.Code, code)
+			}
+			return nil
+		}
+	})
+}
+
+// Done performs the opening handshake and runs the messages
+// exchange based on the defined expectations.
+func (e *Expect) Done() error {
+	conn, res, err := e.socket.Dial()
+	if err != nil {
+		if res != nil {
+			err = fmt.Errorf("websocket handshake error: %s (status %d)", err, res.StatusCode)
+		} else {
+			err = fmt.Errorf("websocket handshake error: %s", err)
+		}
+		e.test.Error(err)
+		return err
+	}
+	defer conn.Close()
+
+	for _, step := range e.steps {
+		if err = step(conn); err != nil {
+			break
+		}
+	}
+
+	if !e.closed {
+		message := gorilla.FormatCloseMessage(gorilla.CloseNormalClosure, "")
+		conn.WriteControl(gorilla.CloseMessage, message, time.Now().Add(time.Second))
+	}
+
+	if err != nil {
+		_, fileName, line, ok := runtime.Caller(1)
+		if !ok {
+			e.test.Error(err)
+		}
+		e.test.Logf("%s:%d: %s\n", path.Base(fileName), line, err)
+		e.test.Fail()
+	}
+	return err
+}
+
+// End is an alias to `Done()`.
+func (e *Expect) End() error {
+	return e.Done()
+}
+
+func (e *Expect) send(kind int, data []byte) *Expect {
+	return e.step(func(conn *gorilla.Conn) error {
+		return conn.WriteMessage(kind, data)
+	})
+}
+
+func (e *Expect) step(fn func(*gorilla.Conn) error) *Expect {
+	e.steps = append(e.steps, fn)
+	return e
+}
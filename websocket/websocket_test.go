@@ -0,0 +1,174 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+	"github.com/nbio/st"
+	"gopkg.in/h2non/baloo.v3"
+)
+
+// testingMock implements baloo.TestingT, recording the test failures.
+type testingMock struct {
+	failed bool
+}
+
+func (t *testingMock) Error(args ...interface{}) {
+	t.failed = true
+}
+
+func (t *testingMock) Fail() {
+	t.failed = true
+}
+
+func (t *testingMock) Logf(format string, args ...interface{}) {}
+
+func createWebSocketServer() *httptest.Server {
+	upgrader := gorilla.Upgrader{Subprotocols: []string{"chat"}}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(401)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		switch r.URL.Path {
+		case "/hello":
+			session := ""
+			if cookie, err := r.Cookie("session"); err == nil {
+				session = cookie.Value
+			}
+			conn.WriteJSON(map[string]interface{}{
+				"type":    "hello",
+				"user":    r.URL.Query().Get("user"),
+				"session": session,
+				"agent":   r.Header.Get("User-Agent"),
+				"cookie":  r.Header.Get("Cookie"),
+			})
+		case "/close":
+			message := gorilla.FormatCloseMessage(4000, "bye")
+			conn.WriteControl(gorilla.CloseMessage, message, time.Now().Add(time.Second))
+			return
+		}
+
+		// Echo messages until closed
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(kind, data)
+		}
+	}))
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	New(baloo.New(ts.URL).BearerToken("token"), "/echo").
+		Subprotocols("chat").
+		Expect(t).
+		Protocol("chat").
+		SendText("hello").
+		ReceiveText("hello").
+		SendBinary([]byte{1, 2, 3}).
+		ReceiveBinary([]byte{1, 2, 3}).
+		SendJSON(map[string]interface{}{"id": 1, "tags": []string{"a"}, "extra": true}).
+		ReceiveJSON(map[string]interface{}{"id": 1, "tags": []string{"a"}}).
+		SendText("hello world").
+		ReceiveMatch("^hello w.+d$").
+		Close(CloseNormalClosure, "").
+		Closed(CloseNormalClosure).
+		Done()
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	ws := New(baloo.New(ts.URL).BearerToken("token"), "/hello").SetQuery("user", "foo")
+	ws.Request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	ws.Expect(t).
+		ReceiveJSON(map[string]string{"type": "hello", "user": "foo", "session": "abc", "agent": baloo.UserAgent}).
+		Done()
+}
+
+func TestWebSocketDialTwice(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	ws := New(baloo.New(ts.URL).BearerToken("token"), "/hello")
+	ws.Request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	// The handshake middleware must not mutate the original request
+	for i := 0; i < 2; i++ {
+		conn, _, err := ws.Dial()
+		st.Expect(t, err, nil)
+		var message map[string]string
+		st.Expect(t, conn.ReadJSON(&message), nil)
+		conn.Close()
+		st.Expect(t, message["cookie"], "session=abc")
+	}
+	st.Expect(t, ws.Request.Request.Context.Request.Header.Get("Cookie"), "")
+}
+
+func TestWebSocketHandshakeError(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := New(baloo.New(ts.URL), "/echo").Expect(mock).Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, strings.Contains(err.Error(), "(status 401)"), true)
+}
+
+func TestWebSocketClosed(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	ws := New(baloo.New(ts.URL).BearerToken("token"), "/close")
+	ws.Expect(t).Closed(4000).Done()
+
+	mock := &testingMock{}
+	err := ws.Expect(mock).Closed(1000).Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), "WebSocket close code mismatch: 4000 != 1000")
+}
+
+func TestWebSocketReceiveTimeout(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := New(baloo.New(ts.URL).BearerToken("token"), "/echo").
+		Expect(mock).
+		Timeout(20 * time.Millisecond).
+		ReceiveText("hello").
+		Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, strings.Contains(err.Error(), "WebSocket receive error"), true)
+}
+
+func TestWebSocketMismatch(t *testing.T) {
+	ts := createWebSocketServer()
+	defer ts.Close()
+
+	mock := &testingMock{}
+	err := New(baloo.New(ts.URL).BearerToken("token"), "/echo").
+		Expect(mock).
+		SendText("foo").
+		ReceiveText("bar").
+		Done()
+	st.Expect(t, mock.failed, true)
+	st.Expect(t, err.Error(), "Message mismatch:\n\thave: \"foo\"\n\twant: \"bar\"")
+}